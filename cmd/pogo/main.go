@@ -0,0 +1,462 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"pogo"
+	"pogo/src/analysis"
+	"pogo/src/dump"
+	"pogo/src/format"
+	"pogo/src/lexer"
+	"pogo/src/lint"
+	"pogo/src/lsp"
+	"pogo/src/parser"
+	"pogo/src/virtualmachine"
+	"strings"
+)
+
+const usage = `usage:
+  pogo [-strict] [run flags] <file.pogo>          compile and run a program
+  pogo compile [-strict] [-c] [-o out.pbin] <file.pogo>
+                                                  compile a program, or an object with -c
+  pogo link [-o prog.pbin] <a.pbin> <b.pbin>...   link objects into a program
+  pogo run [run flags] <prog.pbin>                run a compiled program
+  pogo fmt [-l] <file.pogo|dir>...                 format files in place, or list the unformatted ones with -l
+  pogo dump [--ast] [--symbols] [--quads] [--format json] <file.pogo>
+                                                  describe the syntax tree, symbols and quads of a file, all of them by default
+  pogo cfg [--dot] <file.pogo>                    print the basic blocks of every function, as a Graphviz graph with --dot
+  pogo lint [-only rule,...] [-disable rule,...] <file.pogo>
+                                                  report likely mistakes, all rules run by default
+  pogo lsp                                        start the language server on stdin and stdout
+
+run flags:
+  -profile                                        report the quads and time spent per function, opcode and line
+  -pprof out.pb.gz                                write a profile for go tool pprof
+  -trace out.txt [-trace-func f,...] [-trace-quads 10-20,...]
+                                                  write every executed quad, - writes to stdout`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		return
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "compile":
+		err = compileCommand(os.Args[2:])
+	case "link":
+		err = linkCommand(os.Args[2:])
+	case "run":
+		err = runCommand(os.Args[2:])
+	case "fmt":
+		err = fmtCommand(os.Args[2:])
+	case "dump":
+		err = dumpCommand(os.Args[2:])
+	case "cfg":
+		err = cfgCommand(os.Args[2:])
+	case "lint":
+		err = lintCommand(os.Args[2:])
+	case "lsp":
+		err = lsp.NewServer(os.Stdin, os.Stdout).Serve()
+	default:
+		err = compileAndRun(os.Args[1:])
+	}
+
+	var compileErr *pogo.CompileError
+	if errors.As(err, &compileErr) {
+		fmt.Fprintln(os.Stderr, compileErr)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func compileAndRun(args []string) error {
+	flags := flag.NewFlagSet("pogo", flag.ExitOnError)
+	strict := flags.Bool("strict", false, "treat variables that may be used before being assigned as errors")
+	run := newRunFlags(flags)
+	files, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	program, err := compile(files[0], *strict)
+	if err != nil {
+		return err
+	}
+
+	return run.run(program, files[0])
+}
+
+func compileCommand(args []string) error {
+	flags := flag.NewFlagSet("compile", flag.ExitOnError)
+	strict := flags.Bool("strict", false, "treat variables that may be used before being assigned as errors")
+	object := flags.Bool("c", false, "compile to an object file to be linked later, modules need it")
+	output := flags.String("o", "", "output file, the input name with the .pbin extension by default")
+	files, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		*output = strings.TrimSuffix(files[0], filepath.Ext(files[0])) + ".pbin"
+	}
+
+	if !*object {
+		program, err := compile(files[0], *strict)
+		if err != nil {
+			return err
+		}
+		return program.Save(*output)
+	}
+
+	src, err := os.ReadFile(files[0])
+	if err != nil {
+		return fmt.Errorf("error reading input: %v", err)
+	}
+
+	obj, err := pogo.CompileObject(src, pogo.CompileOptions{Filename: files[0], Strict: *strict})
+	if err != nil {
+		return err
+	}
+	for _, diagnostic := range obj.Diagnostics {
+		fmt.Fprintln(os.Stderr, diagnostic)
+	}
+	return obj.Save(*output)
+}
+
+func linkCommand(args []string) error {
+	flags := flag.NewFlagSet("link", flag.ExitOnError)
+	output := flags.String("o", "a.pbin", "output program")
+	files, err := parseArgs(flags, args, -1)
+	if err != nil {
+		return err
+	}
+
+	objects := make([]*pogo.Object, len(files))
+	for i, file := range files {
+		if objects[i], err = pogo.LoadObject(file); err != nil {
+			return err
+		}
+	}
+
+	program, err := pogo.Link(objects...)
+	if err != nil {
+		return err
+	}
+	return program.Save(*output)
+}
+
+func runCommand(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	run := newRunFlags(flags)
+	files, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	program, err := pogo.Load(files[0])
+	if err != nil {
+		return err
+	}
+	return run.run(program, files[0])
+}
+
+// runFlags are the flags of the commands that run a program.
+type runFlags struct {
+	report         *bool
+	pprof          *string
+	trace          *string
+	traceFunctions *string
+	traceQuads     *string
+}
+
+func newRunFlags(flags *flag.FlagSet) runFlags {
+	return runFlags{
+		report:         flags.Bool("profile", false, "print the quads and time spent per function, opcode and line after the run"),
+		pprof:          flags.String("pprof", "", "write a profile of the run for go tool pprof"),
+		trace:          flags.String("trace", "", "write every executed quad to a file, - for stdout"),
+		traceFunctions: flags.String("trace-func", "", "comma separated functions to trace, <program> for the main section"),
+		traceQuads:     flags.String("trace-quads", "", "comma separated quad ranges to trace, e.g. 10-20,35"),
+	}
+}
+
+// run runs program, profiling or tracing it when asked to. The profile
+// report goes to stderr so it doesn't mix with the output of the program.
+func (f runFlags) run(program *pogo.Program, filename string) error {
+	opts := pogo.RunOptions{}
+	if *f.report || *f.pprof != "" {
+		opts.Profile = virtualmachine.NewProfile()
+		opts.Profile.Filename = filename
+	}
+
+	if *f.trace != "" {
+		trace, closeTrace, err := f.newTrace()
+		if err != nil {
+			return err
+		}
+		defer closeTrace()
+		opts.Trace = trace
+	}
+
+	runErr := program.Run(context.Background(), opts)
+	if opts.Profile == nil {
+		return runErr
+	}
+
+	if *f.report {
+		if err := opts.Profile.WriteReport(os.Stderr); err != nil {
+			return err
+		}
+	}
+	if *f.pprof != "" {
+		out, err := os.Create(*f.pprof)
+		if err != nil {
+			return fmt.Errorf("error writing profile: %v", err)
+		}
+		defer out.Close()
+		if err := opts.Profile.WritePprof(out); err != nil {
+			return fmt.Errorf("error writing profile: %v", err)
+		}
+	}
+	return runErr
+}
+
+// newTrace opens the trace output and reads its filters, the returned
+// function closes the output.
+func (f runFlags) newTrace() (*virtualmachine.Trace, func() error, error) {
+	trace := &virtualmachine.Trace{Functions: splitList(*f.traceFunctions)}
+	for _, quads := range splitList(*f.traceQuads) {
+		r, err := virtualmachine.ParseQuadRange(quads)
+		if err != nil {
+			return nil, nil, err
+		}
+		trace.Ranges = append(trace.Ranges, r)
+	}
+
+	if *f.trace == "-" {
+		trace.Output = os.Stdout
+		return trace, func() error { return nil }, nil
+	}
+
+	out, err := os.Create(*f.trace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error writing trace: %v", err)
+	}
+	buffered := bufio.NewWriter(out)
+	trace.Output = buffered
+	return trace, func() error {
+		if err := buffered.Flush(); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	}, nil
+}
+
+func fmtCommand(args []string) error {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	var check bool
+	flags.BoolVar(&check, "l", false, "list the files that are not formatted instead of rewriting them, and fail if there are any")
+	flags.BoolVar(&check, "check", false, "same as -l")
+	paths, err := parseArgs(flags, args, -1)
+	if err != nil {
+		return err
+	}
+
+	// Directories are searched for .pogo files
+	var files []string
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && (file == path || filepath.Ext(file) == ".pogo") {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	unformatted := 0
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("error reading input: %v", err)
+		}
+		formatted, err := format.Source(src)
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		if bytes.Equal(src, formatted) {
+			continue
+		}
+
+		if check {
+			fmt.Println(file)
+			unformatted++
+		} else if err := os.WriteFile(file, formatted, 0644); err != nil {
+			return err
+		}
+	}
+
+	if unformatted > 0 {
+		return fmt.Errorf("%d file(s) not formatted", unformatted)
+	}
+	return nil
+}
+
+func dumpCommand(args []string) error {
+	flags := flag.NewFlagSet("dump", flag.ExitOnError)
+	var sections dump.Sections
+	flags.BoolVar(&sections.AST, "ast", false, "describe the syntax tree")
+	flags.BoolVar(&sections.Symbols, "symbols", false, "describe the scopes, functions, struct types and constants")
+	flags.BoolVar(&sections.Quads, "quads", false, "describe the quadruples")
+	outputFormat := flags.String("format", "json", "output format, only json is supported")
+	files, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	if *outputFormat != "json" {
+		return fmt.Errorf("unsupported format '%s', expected json", *outputFormat)
+	}
+	if sections == (dump.Sections{}) {
+		sections = dump.Sections{AST: true, Symbols: true, Quads: true}
+	}
+
+	src, err := os.ReadFile(files[0])
+	if err != nil {
+		return fmt.Errorf("error reading input: %v", err)
+	}
+
+	doc, err := dump.Source(src, files[0], sections)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(doc)
+}
+
+func cfgCommand(args []string) error {
+	flags := flag.NewFlagSet("cfg", flag.ExitOnError)
+	dot := flags.Bool("dot", false, "print the graph in the DOT language of Graphviz")
+	files, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.ReadFile(files[0])
+	if err != nil {
+		return fmt.Errorf("error reading input: %v", err)
+	}
+
+	p := parser.NewParser(lexer.NewLexer(src))
+	p.Filename = files[0]
+	if _, err := p.Parse(); err != nil {
+		return err
+	}
+
+	quads := p.CodeGenerator.Quads
+	graph := analysis.BuildControlFlowGraph(quads, analysis.FunctionStarts(p.SymbolTable.GetFunctionInfos()))
+	names := analysis.NewAddressNames(p.SymbolTable, p.CodeGenerator.MemoryManager)
+	if *dot {
+		return analysis.WriteDot(os.Stdout, graph, names)
+	}
+	return analysis.WriteBlocks(os.Stdout, graph, names)
+}
+
+func lintCommand(args []string) error {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	only := flags.String("only", "", "comma separated rules to run instead of all of them")
+	disable := flags.String("disable", "", "comma separated rules not to run")
+	files, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.ReadFile(files[0])
+	if err != nil {
+		return fmt.Errorf("error reading input: %v", err)
+	}
+
+	diagnostics, err := lint.Source(src, files[0], lint.Config{Only: splitList(*only), Disable: splitList(*disable)})
+	if err != nil {
+		return err
+	}
+
+	for _, diagnostic := range diagnostics {
+		fmt.Printf("%s: %s\n", files[0], diagnostic)
+	}
+	if len(diagnostics) > 0 {
+		return fmt.Errorf("%d problem(s) found", len(diagnostics))
+	}
+	return nil
+}
+
+// splitList splits a comma separated list of names.
+func splitList(list string) []string {
+	names := make([]string, 0)
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func compile(filename string, strict bool) (*pogo.Program, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading input: %v", err)
+	}
+
+	program, err := pogo.Compile(src, pogo.CompileOptions{Filename: filename, Strict: strict})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, diagnostic := range program.Diagnostics {
+		fmt.Fprintln(os.Stderr, diagnostic)
+	}
+	return program, nil
+}
+
+// parseArgs parses flags that may come before or after the file arguments
+// and checks that there are exactly count files, or at least one when count
+// is negative.
+func parseArgs(flags *flag.FlagSet, args []string, count int) ([]string, error) {
+	var files []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		files = append(files, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+
+	if (count < 0 && len(files) == 0) || (count >= 0 && len(files) != count) {
+		return nil, errors.New(usage)
+	}
+	return files, nil
+}
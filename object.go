@@ -0,0 +1,85 @@
+package pogo
+
+import (
+	"fmt"
+	"pogo/src/linker"
+	"pogo/src/shared"
+	"pogo/src/storer"
+)
+
+// Object is a compiled file that still has to be linked with the objects
+// of the modules it imports before it can run.
+type Object struct {
+	unit    *linker.Unit
+	natives *Natives
+
+	// Diagnostics holds the warnings found while compiling.
+	Diagnostics []shared.Diagnostic
+}
+
+// CompileObject compiles a program or a module on its own. Imported modules
+// are only read to check the calls to their functions, their code comes
+// from their own objects when linking.
+func CompileObject(src []byte, opts CompileOptions) (*Object, error) {
+	p := newParser(src, opts)
+	p.SeparateImports = true
+
+	isModule, err := p.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics, err := check(p.CodeGenerator.Quads, p.SymbolTable, opts.Strict)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := p.Unit()
+	unit.Main = !isModule
+	return &Object{unit: unit, natives: opts.Natives, Diagnostics: diagnostics}, nil
+}
+
+// LoadObject reads an object saved with Object.Save. Like a loaded program,
+// it has lost the natives it was compiled with.
+func LoadObject(filename string) (*Object, error) {
+	unit, err := storer.LoadObject(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &Object{unit: unit}, nil
+}
+
+func (o *Object) Save(filename string) error {
+	return storer.SaveObject(o.unit, filename)
+}
+
+// Link combines objects into a program, exactly one of them must have a
+// main section. Every external function must be defined by one of them.
+// The program runs with the natives the objects were compiled with, which
+// must be the same set for all of them.
+func Link(objects ...*Object) (*Program, error) {
+	units := make([]*linker.Unit, len(objects))
+	var natives *Natives
+	for i, object := range objects {
+		units[i] = object.unit
+		if object.natives == nil {
+			continue
+		}
+		if natives != nil && natives != object.natives {
+			return nil, fmt.Errorf("objects compiled with different natives can't be linked")
+		}
+		natives = object.natives
+	}
+
+	quads, st, err := linker.LinkObjects(units)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Program{
+		quads:     quads.Quads,
+		functions: st.GetFunctionInfos(),
+		memory:    quads.MemoryManager,
+		natives:   natives,
+	}, nil
+}
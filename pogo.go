@@ -0,0 +1,209 @@
+// Package pogo compiles and runs Pogo programs from Go.
+//
+//	program, err := pogo.Compile(src, pogo.CompileOptions{})
+//	if err != nil {
+//		return err
+//	}
+//	err = program.Run(ctx, pogo.RunOptions{Output: &out})
+package pogo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"pogo/src/analysis"
+	"pogo/src/lexer"
+	"pogo/src/native"
+	"pogo/src/parser"
+	"pogo/src/semantic"
+	"pogo/src/shared"
+	"pogo/src/storer"
+	"pogo/src/virtualmachine"
+	"strings"
+)
+
+// Natives holds host functions callable from Pogo, register them with
+// RegisterNative and pass the same set to Compile and Run.
+type Natives = native.Registry
+
+// Value is an argument or the result of a native function.
+type Value = native.Value
+
+// Signature declares the parameter and result types of a native function.
+type Signature = native.Signature
+
+// Type is the type of a Value or of a native function parameter.
+type Type = shared.Type
+
+const (
+	TypeInt   = shared.TypeInt
+	TypeFloat = shared.TypeFloat
+	TypeVoid  = shared.TypeVoid
+)
+
+func NewNatives() *Natives {
+	return native.NewRegistry()
+}
+
+func IntValue(v int) Value {
+	return native.Int(v)
+}
+
+func FloatValue(v float64) Value {
+	return native.Float(v)
+}
+
+// VoidValue is the result of native functions that return nothing.
+func VoidValue() Value {
+	return native.Void()
+}
+
+type CompileOptions struct {
+	// Filename is the name of the compiled file, imports are resolved
+	// relative to its directory.
+	Filename string
+	// FS is where imported modules are read from, the disk when nil.
+	FS fs.FS
+	// Strict turns the warnings of the definite assignment analysis into errors.
+	Strict bool
+	// Natives are the host functions the program may call.
+	Natives *Natives
+}
+
+type RunOptions struct {
+	// Output receives everything the program prints, os.Stdout when nil.
+	Output io.Writer
+	// Natives replaces the host functions given to Compile, programs read
+	// with Load need it to call native functions.
+	Natives *Natives
+	// Profile, when set, is filled with what the run spent its time on, see
+	// virtualmachine.Profile.
+	Profile *virtualmachine.Profile
+	// Trace, when set, writes a line for every quad the run executes, see
+	// virtualmachine.Trace.
+	Trace *virtualmachine.Trace
+}
+
+// Program is a compiled Pogo program. It can be run any number of times,
+// also concurrently, every run starts with fresh memory.
+type Program struct {
+	quads     []shared.Quadruple
+	functions map[string]shared.FunctionInfo
+	memory    *virtualmachine.MemoryManager
+	natives   *Natives
+
+	// Diagnostics holds the warnings found while compiling.
+	Diagnostics []shared.Diagnostic
+}
+
+// CompileError is returned when the analysis of a program finds errors.
+type CompileError struct {
+	Diagnostics []shared.Diagnostic
+}
+
+func (e *CompileError) Error() string {
+	messages := make([]string, len(e.Diagnostics))
+	for i, diagnostic := range e.Diagnostics {
+		messages[i] = diagnostic.String()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Compile parses and checks src and generates its code in memory, imported
+// modules are compiled and linked into the same program.
+func Compile(src []byte, opts CompileOptions) (*Program, error) {
+	p := newParser(src, opts)
+	if err := p.ParseProgram(); err != nil {
+		return nil, err
+	}
+
+	diagnostics, err := check(p.CodeGenerator.Quads, p.SymbolTable, opts.Strict)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Program{
+		quads:       p.CodeGenerator.Quads,
+		functions:   p.SymbolTable.GetFunctionInfos(),
+		memory:      p.CodeGenerator.MemoryManager,
+		natives:     opts.Natives,
+		Diagnostics: diagnostics,
+	}, nil
+}
+
+func newParser(src []byte, opts CompileOptions) *parser.Parser {
+	p := parser.NewParser(lexer.NewLexer(src))
+	p.SymbolTable.Natives = opts.Natives
+	p.Filename = opts.Filename
+	if opts.FS != nil {
+		p.ReadFile = func(name string) ([]byte, error) {
+			return fs.ReadFile(opts.FS, filepath.ToSlash(name))
+		}
+	}
+	return p
+}
+
+// check runs the analysis of a parsed program, it returns the warnings or a
+// CompileError with every error found.
+func check(quads []shared.Quadruple, st *semantic.SymbolTable, strict bool) ([]shared.Diagnostic, error) {
+	var warnings, errors []shared.Diagnostic
+	for _, diagnostic := range analysis.CheckDefiniteAssignment(quads, st, strict) {
+		if diagnostic.Severity == shared.SeverityError {
+			errors = append(errors, diagnostic)
+		} else {
+			warnings = append(warnings, diagnostic)
+		}
+	}
+	if len(errors) > 0 {
+		return nil, &CompileError{Diagnostics: errors}
+	}
+	return warnings, nil
+}
+
+// Load reads a program saved with Save or compiled by the pogo command.
+func Load(filename string) (*Program, error) {
+	vmData, err := storer.ReadCompiledData(filename)
+	if err != nil {
+		if _, objectErr := storer.LoadObject(filename); objectErr == nil {
+			return nil, fmt.Errorf("%s is an object file, link it into a program first", filename)
+		}
+		return nil, err
+	}
+
+	return &Program{
+		quads:     vmData.Quadruples,
+		functions: vmData.Functions,
+		memory:    vmData.MemoryManager,
+	}, nil
+}
+
+// Save writes the compiled program to filename.
+func (p *Program) Save(filename string) error {
+	return storer.WriteCompiledData(storer.SerializedVMData{
+		Quadruples:    p.quads,
+		Functions:     p.functions,
+		MemoryManager: p.memory,
+	}, filename)
+}
+
+// Run executes the program until it ends, fails or ctx is done.
+func (p *Program) Run(ctx context.Context, opts RunOptions) error {
+	vm := virtualmachine.NewVirtualMachine(p.quads, p.memory.Clone())
+	vm.Functions = p.functions
+	vm.Natives = p.natives
+	if opts.Natives != nil {
+		vm.Natives = opts.Natives
+	}
+	if opts.Output != nil {
+		vm.Output = opts.Output
+	}
+	vm.Profile = opts.Profile
+	vm.Trace = opts.Trace
+
+	if err := vm.ExecuteContext(ctx); err != nil {
+		return fmt.Errorf("runtime error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package analysis
+
+import (
+	"pogo/src/shared"
+	"pogo/src/virtualmachine"
+	"sort"
+)
+
+// ProgramBody is the name used for the code outside of any function: the
+// global initializers and the main section. Profiles and traces use the
+// same name.
+const ProgramBody = virtualmachine.ProgramBody
+
+type BasicBlock struct {
+	Index        int
+	Start        int // first quad of the block
+	End          int // one past the last quad of the block
+	Function     string
+	Successors   []int
+	Predecessors []int
+}
+
+type ControlFlowGraph struct {
+	Quads   []shared.Quadruple
+	Blocks  []*BasicBlock
+	Entries map[string]int // entry block of every function and of the program body
+	blockOf []int          // block index of every quad
+}
+
+// BuildControlFlowGraph splits quads into basic blocks at jump targets,
+// after jumps and calls, and at the start of every function.
+func BuildControlFlowGraph(quads []shared.Quadruple, functionStarts map[string]int) *ControlFlowGraph {
+	g := &ControlFlowGraph{
+		Quads:   quads,
+		Entries: make(map[string]int),
+		blockOf: make([]int, len(quads)),
+	}
+
+	if len(quads) == 0 {
+		return g
+	}
+
+	leaders := map[int]bool{0: true}
+	for _, start := range functionStarts {
+		leaders[start] = true
+	}
+	for i, quad := range quads {
+		for _, target := range quad.JumpTargets() {
+			leaders[target] = true
+		}
+		if endsBlock(quad) {
+			leaders[i+1] = true
+		}
+	}
+
+	starts := make([]int, 0, len(leaders))
+	for leader := range leaders {
+		if leader >= 0 && leader < len(quads) {
+			starts = append(starts, leader)
+		}
+	}
+	sort.Ints(starts)
+
+	for i, start := range starts {
+		end := len(quads)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		block := &BasicBlock{Index: i, Start: start, End: end}
+		g.Blocks = append(g.Blocks, block)
+		for q := start; q < end; q++ {
+			g.blockOf[q] = i
+		}
+	}
+
+	for _, block := range g.Blocks {
+		for _, target := range g.successorQuads(block) {
+			if target >= len(quads) {
+				continue
+			}
+			succ := g.blockOf[target]
+			if !containsInt(block.Successors, succ) {
+				block.Successors = append(block.Successors, succ)
+				g.Blocks[succ].Predecessors = append(g.Blocks[succ].Predecessors, block.Index)
+			}
+		}
+	}
+
+	g.Entries[ProgramBody] = 0
+	g.assignFunction(0, ProgramBody)
+	for name, start := range functionStarts {
+		if start < 0 || start >= len(quads) {
+			continue
+		}
+		g.Entries[name] = g.blockOf[start]
+		g.assignFunction(g.blockOf[start], name)
+	}
+
+	return g
+}
+
+// BlockOf returns the block that contains the given quad.
+func (g *ControlFlowGraph) BlockOf(quad int) *BasicBlock {
+	return g.Blocks[g.blockOf[quad]]
+}
+
+// Last returns the last quad of a block.
+func (g *ControlFlowGraph) Last(block *BasicBlock) shared.Quadruple {
+	return g.Quads[block.End-1]
+}
+
+func (g *ControlFlowGraph) successorQuads(block *BasicBlock) []int {
+	last := g.Last(block)
+	switch last.Operator {
+	case "goto":
+		return last.JumpTargets()
+	case "endproc":
+		return nil
+	case "gosub":
+		// The call continues at the next quad once the function returns.
+		return []int{block.End}
+	default:
+		// gotof and jumptable fall through when no jump is taken.
+		return append(last.JumpTargets(), block.End)
+	}
+}
+
+// assignFunction marks every block reachable from entry as part of function.
+func (g *ControlFlowGraph) assignFunction(entry int, function string) {
+	visited := map[int]bool{entry: true}
+	pending := []int{entry}
+	for len(pending) > 0 {
+		block := g.Blocks[pending[len(pending)-1]]
+		pending = pending[:len(pending)-1]
+		block.Function = function
+		for _, succ := range block.Successors {
+			if !visited[succ] {
+				visited[succ] = true
+				pending = append(pending, succ)
+			}
+		}
+	}
+}
+
+func endsBlock(quad shared.Quadruple) bool {
+	switch quad.Operator {
+	case "goto", "gotof", "jumptable", "gosub", "endproc":
+		return true
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,365 @@
+package analysis
+
+import (
+	"fmt"
+	"pogo/src/semantic"
+	"pogo/src/shared"
+	"pogo/src/virtualmachine"
+	"sort"
+)
+
+// assignedSet holds the addresses that are definitely assigned at a program
+// point. A nil assignedSet means the point has not been reached (yet).
+type assignedSet map[int]bool
+
+func (s assignedSet) clone() assignedSet {
+	c := make(assignedSet, len(s))
+	for addr := range s {
+		c[addr] = true
+	}
+	return c
+}
+
+// meet intersects two facts, nil stands for "unreached" and is neutral.
+func meet(a, b assignedSet) assignedSet {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	result := make(assignedSet)
+	for addr := range a {
+		if b[addr] {
+			result[addr] = true
+		}
+	}
+	return result
+}
+
+func equal(a, b assignedSet) bool {
+	if (a == nil) != (b == nil) || len(a) != len(b) {
+		return false
+	}
+	for addr := range a {
+		if !b[addr] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s assignedSet) globals() assignedSet {
+	result := make(assignedSet)
+	for addr := range s {
+		if isGlobal(addr) {
+			result[addr] = true
+		}
+	}
+	return result
+}
+
+func isGlobal(addr int) bool {
+	return addr >= virtualmachine.GLOBAL_START && addr < virtualmachine.LOCAL_START
+}
+
+func isLocal(addr int) bool {
+	return addr >= virtualmachine.LOCAL_START && addr < virtualmachine.TEMP_START
+}
+
+// definiteAssignment runs a forward must-analysis over the control flow graph
+// of the whole program. Calls are followed context-insensitively: a function
+// starts with the globals assigned at every one of its call sites and a call
+// adds the globals assigned when the callee returns. A variable passed by
+// reference is assigned by the call, the parameter it is passed as starts
+// assigned only when every call site passes an assigned variable.
+type definiteAssignment struct {
+	graph     *ControlFlowGraph
+	functions map[string]shared.Function
+	names     map[string]map[int]string // variable names by owner and address
+	callers   map[string][]int          // gosub quads of every function
+	refs      map[int]map[int]int       // the addresses passed by reference at every gosub, by parameter slot
+
+	in        map[int]assignedSet
+	out       map[int]assignedSet
+	callSites map[int]assignedSet
+	refSites  map[int]map[int]bool // whether each of refs was assigned at the gosub
+}
+
+// CheckDefiniteAssignment reports the variables that may be read before
+// they are assigned. In strict mode the findings are errors, otherwise
+// they are warnings.
+func CheckDefiniteAssignment(quads []shared.Quadruple, st *semantic.SymbolTable, strict bool) []shared.Diagnostic {
+	da := &definiteAssignment{
+		functions: make(map[string]shared.Function),
+		names:     make(map[string]map[int]string),
+		callers:   make(map[string][]int),
+		refs:      make(map[int]map[int]int),
+		in:        make(map[int]assignedSet),
+		out:       make(map[int]assignedSet),
+		callSites: make(map[int]assignedSet),
+		refSites:  make(map[int]map[int]bool),
+	}
+
+	starts := make(map[string]int)
+	for name, symbol := range st.GetGlobalScope() {
+		// External functions of an object file have no code to analyze
+		if function, ok := symbol.(shared.Function); ok && function.StartQuad >= 0 {
+			da.functions[name] = function
+			starts[name] = function.StartQuad
+		}
+	}
+
+	for scope, symbols := range st.GetScopes() {
+		for name, symbol := range symbols {
+			variable, ok := symbol.(shared.Variable)
+			if !ok {
+				continue
+			}
+			// A struct has no address of its own, its first field tells the segment
+			owner := semantic.ScopeOwner(scope)
+			first := shared.Scalars([]shared.Variable{variable})[0]
+			if owner == "global" || owner == semantic.MainScope || isGlobal(first.Address) {
+				owner = ProgramBody
+			}
+			if da.names[owner] == nil {
+				da.names[owner] = make(map[int]string)
+			}
+			da.addName(owner, name, variable)
+		}
+	}
+
+	// Calls made while evaluating arguments nest between the era and the
+	// gosub of the call they are arguments of
+	calls := make([]map[int]int, 0)
+	for i, quad := range quads {
+		switch quad.Operator {
+		case "era":
+			calls = append(calls, make(map[int]int))
+		case "paramref":
+			if len(calls) > 0 {
+				calls[len(calls)-1][quad.RightOp.(int)] = quad.LeftOp.(int)
+			}
+		case "gosub":
+			name := quad.LeftOp.(string)
+			da.callers[name] = append(da.callers[name], i)
+			if len(calls) > 0 {
+				da.refs[i] = calls[len(calls)-1]
+				calls = calls[:len(calls)-1]
+			}
+		}
+	}
+
+	da.graph = BuildControlFlowGraph(quads, starts)
+	da.solve()
+
+	severity := shared.SeverityWarning
+	if strict {
+		severity = shared.SeverityError
+	}
+	return da.report(severity)
+}
+
+func (da *definiteAssignment) solve() {
+	for changed := true; changed; {
+		changed = false
+		for _, block := range da.graph.Blocks {
+			in := da.blockInput(block)
+			if in == nil {
+				continue
+			}
+			out := da.transfer(block, in.clone(), nil)
+			if !equal(in, da.in[block.Index]) || !equal(out, da.out[block.Index]) {
+				da.in[block.Index] = in
+				da.out[block.Index] = out
+				changed = true
+			}
+		}
+	}
+}
+
+func (da *definiteAssignment) blockInput(block *BasicBlock) assignedSet {
+	var in assignedSet
+	for _, pred := range block.Predecessors {
+		in = meet(in, da.out[pred])
+	}
+
+	if entry, ok := da.graph.Entries[block.Function]; ok && entry == block.Index {
+		in = meet(in, da.entryFact(block.Function))
+	}
+
+	return in
+}
+
+// entryFact returns what is assigned when a function starts: its parameters
+// and the globals assigned at all of its call sites. A function that is never
+// called cannot observe unassigned globals, so only its locals are checked.
+// A parameter passed by reference is left out when a call site passes an
+// unassigned variable.
+func (da *definiteAssignment) entryFact(function string) assignedSet {
+	if function == ProgramBody {
+		return assignedSet{}
+	}
+
+	var entry assignedSet
+	if len(da.callers[function]) == 0 {
+		entry = make(assignedSet)
+		for addr := range da.names[ProgramBody] {
+			if isGlobal(addr) {
+				entry[addr] = true
+			}
+		}
+	} else {
+		for _, site := range da.callers[function] {
+			entry = meet(entry, da.callSites[site])
+		}
+		if entry == nil {
+			return nil
+		}
+		entry = entry.clone()
+	}
+
+	for slot, param := range shared.Scalars(da.functions[function].Parameters) {
+		assigned := true
+		for _, site := range da.callers[function] {
+			if refAssigned, ok := da.refSites[site][slot]; ok && !refAssigned {
+				assigned = false
+			}
+		}
+		if assigned {
+			entry[param.Address] = true
+		}
+	}
+	return entry
+}
+
+// exitFact returns the globals assigned when a function returns.
+func (da *definiteAssignment) exitFact(function string) assignedSet {
+	var exit assignedSet
+	for _, block := range da.graph.Blocks {
+		if block.Function == function && da.graph.Last(block).Operator == "endproc" {
+			exit = meet(exit, da.out[block.Index])
+		}
+	}
+	if exit == nil {
+		return nil
+	}
+	return exit.globals()
+}
+
+// addName names the address of a variable, the fields of a struct are
+// named after their path like p.x.
+func (da *definiteAssignment) addName(owner, name string, variable shared.Variable) {
+	if variable.Type != shared.TypeStruct {
+		da.names[owner][variable.Address] = name
+		return
+	}
+	for _, field := range variable.Fields {
+		da.addName(owner, name+"."+field.Name, field)
+	}
+}
+
+// defines reports whether the code of a function is part of the program,
+// calls to external functions are assumed to assign no globals.
+func (da *definiteAssignment) defines(function string) bool {
+	_, exists := da.functions[function]
+	return exists
+}
+
+// transfer applies the quads of a block to fact. When read is not nil it is
+// called for every address read before the quad that reads it is applied.
+func (da *definiteAssignment) transfer(block *BasicBlock, fact assignedSet, read func(quad shared.Quadruple, addr int, fact assignedSet)) assignedSet {
+	for i := block.Start; i < block.End; i++ {
+		quad := da.graph.Quads[i]
+
+		if read != nil {
+			for _, addr := range quad.ReadAddresses() {
+				read(quad, addr, fact)
+			}
+		}
+
+		if addr, ok := quad.WrittenAddress(); ok && (isGlobal(addr) || isLocal(addr)) {
+			fact[addr] = true
+		}
+
+		if quad.Operator == "gosub" && da.defines(quad.LeftOp.(string)) {
+			da.callSites[i] = fact.globals()
+			da.refSites[i] = make(map[int]bool)
+			for slot, addr := range da.refs[i] {
+				da.refSites[i][slot] = fact[addr]
+			}
+			exit := da.exitFact(quad.LeftOp.(string))
+			if exit == nil {
+				return nil
+			}
+			for addr := range exit {
+				fact[addr] = true
+			}
+		}
+		if quad.Operator == "gosub" {
+			for _, addr := range da.refs[i] {
+				if isGlobal(addr) || isLocal(addr) {
+					fact[addr] = true
+				}
+			}
+		}
+	}
+	return fact
+}
+
+func (da *definiteAssignment) report(severity shared.Severity) []shared.Diagnostic {
+	diagnostics := make([]shared.Diagnostic, 0)
+	reported := make(map[string]bool)
+
+	for _, block := range da.graph.Blocks {
+		in := da.in[block.Index]
+		if in == nil {
+			continue
+		}
+
+		da.transfer(block, in.clone(), func(quad shared.Quadruple, addr int, fact assignedSet) {
+			if fact[addr] || !(isGlobal(addr) || isLocal(addr)) {
+				return
+			}
+
+			owner := block.Function
+			if isGlobal(addr) {
+				owner = ProgramBody
+			}
+			name, ok := da.names[owner][addr]
+			if !ok && isGlobal(addr) {
+				// A global of a module compiled separately, its own
+				// module is in charge of assigning it
+				return
+			}
+			if !ok {
+				name = fmt.Sprintf("at address %d", addr)
+			}
+
+			key := fmt.Sprintf("%d:%s:%s:%s", quad.Line, block.Function, owner, name)
+			if reported[key] {
+				return
+			}
+			reported[key] = true
+
+			diagnostics = append(diagnostics, shared.Diagnostic{
+				Severity: severity,
+				File:     da.functions[block.Function].Module,
+				Line:     quad.Line,
+				Column:   quad.Column,
+				Message:  fmt.Sprintf("variable '%s' may be used before being assigned", name),
+			})
+		})
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].File != diagnostics[j].File {
+			return diagnostics[i].File < diagnostics[j].File
+		}
+		if diagnostics[i].Line != diagnostics[j].Line {
+			return diagnostics[i].Line < diagnostics[j].Line
+		}
+		return diagnostics[i].Column < diagnostics[j].Column
+	})
+	return diagnostics
+}
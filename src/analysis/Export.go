@@ -0,0 +1,247 @@
+package analysis
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"pogo/src/semantic"
+	"pogo/src/shared"
+	"pogo/src/virtualmachine"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FunctionStarts returns the first quad of every function that has code in
+// quads, the starts BuildControlFlowGraph splits the program at.
+func FunctionStarts(functions map[string]shared.FunctionInfo) map[string]int {
+	starts := make(map[string]int)
+	for name, function := range functions {
+		if function.StartQuad >= 0 {
+			starts[name] = function.StartQuad
+		}
+	}
+	return starts
+}
+
+// AddressNames names the addresses quads use: variables by the names they
+// are declared with, constants by their value and temporaries as t0, t1...
+// Locals are named per function, the same address is a different variable
+// in each of them.
+type AddressNames struct {
+	names     map[string]map[int]string // variable names by owner and address
+	constants map[int]interface{}
+}
+
+// NewAddressNames collects the names of the variables in st and the
+// constants in memory.
+func NewAddressNames(st *semantic.SymbolTable, memory *virtualmachine.MemoryManager) *AddressNames {
+	an := &AddressNames{
+		names:     make(map[string]map[int]string),
+		constants: memory.ConstantMapLoad,
+	}
+
+	// Modules come first, the names of the file itself win over theirs
+	scopes := make([]string, 0)
+	for scope := range st.GetScopes() {
+		scopes = append(scopes, scope)
+	}
+	sort.Slice(scopes, func(i, j int) bool {
+		moduleI, moduleJ := strings.HasPrefix(scopes[i], "module:"), strings.HasPrefix(scopes[j], "module:")
+		if moduleI != moduleJ {
+			return moduleI
+		}
+		return scopes[i] < scopes[j]
+	})
+
+	for _, scope := range scopes {
+		owner := semantic.ScopeOwner(scope)
+		if owner == "global" || owner == semantic.MainScope || strings.HasPrefix(owner, "module:") {
+			owner = ProgramBody
+		}
+		for name, symbol := range st.GetScopes()[scope] {
+			if variable, ok := symbol.(shared.Variable); ok && !variable.Constant {
+				an.add(owner, name, variable)
+			}
+		}
+	}
+	return an
+}
+
+func (an *AddressNames) add(owner, name string, variable shared.Variable) {
+	if variable.Type == shared.TypeStruct {
+		for _, field := range variable.Fields {
+			an.add(owner, name+"."+field.Name, field)
+		}
+		return
+	}
+	if an.names[owner] == nil {
+		an.names[owner] = make(map[int]string)
+	}
+	an.names[owner][variable.Address] = name
+}
+
+// Name returns what addr is in the code of function.
+func (an *AddressNames) Name(function string, addr int) string {
+	switch {
+	case addr >= virtualmachine.CONSTANT_START:
+		if value, ok := an.constants[addr-virtualmachine.CONSTANT_START]; ok {
+			if s, ok := value.(string); ok && !strings.HasPrefix(s, `"`) {
+				return strconv.Quote(s)
+			}
+			return fmt.Sprint(value)
+		}
+	case addr >= virtualmachine.TEMP_START:
+		return "t" + strconv.Itoa(addr-virtualmachine.TEMP_START)
+	case addr >= virtualmachine.LOCAL_START:
+		if name, ok := an.names[function][addr]; ok {
+			return name
+		}
+	default:
+		if name, ok := an.names[ProgramBody][addr]; ok {
+			return name
+		}
+	}
+	return "@" + strconv.Itoa(addr)
+}
+
+// Label writes a quad of function as a line of pseudo code, e.g.
+// "t0 = n - 1" or "gotof t1 -> 12".
+func (an *AddressNames) Label(function string, quad shared.Quadruple) string {
+	name := func(operand interface{}) string {
+		return an.Name(function, operand.(int))
+	}
+	list := func(operand interface{}) string {
+		addresses, _ := operand.([]int)
+		names := make([]string, len(addresses))
+		for i, addr := range addresses {
+			names[i] = an.Name(function, addr)
+		}
+		return strings.Join(names, ", ")
+	}
+
+	switch quad.Operator {
+	case "=":
+		return fmt.Sprintf("%s = %s", name(quad.Result), name(quad.LeftOp))
+	case "int", "float", "round", "floor", "ceil":
+		return fmt.Sprintf("%s = %s(%s)", name(quad.Result), quad.Operator, name(quad.LeftOp))
+	case "print":
+		return "print " + list(quad.LeftOp)
+	case "goto":
+		return fmt.Sprintf("goto %d", quad.Result)
+	case "gotof":
+		return fmt.Sprintf("gotof %s -> %d", name(quad.LeftOp), quad.Result)
+	case "jumptable":
+		return fmt.Sprintf("jumptable %s from %d -> %v", name(quad.LeftOp), quad.RightOp, quad.Result)
+	case "era":
+		return fmt.Sprintf("era %s", quad.LeftOp)
+	case "param", "paramref":
+		return fmt.Sprintf("%s %s -> #%d", quad.Operator, name(quad.LeftOp), quad.RightOp)
+	case "gosub":
+		return fmt.Sprintf("gosub %s -> %d", quad.LeftOp, quad.Result)
+	case "endproc":
+		return "endproc"
+	case "frame":
+		return fmt.Sprintf("frame %d int, %d float", quad.LeftOp, quad.RightOp)
+	case "callbuiltin", "callnative":
+		call := fmt.Sprintf("%s(%s)", quad.LeftOp, list(quad.RightOp))
+		if quad.Result == nil {
+			return call
+		}
+		return fmt.Sprintf("%s = %s", name(quad.Result), call)
+	}
+
+	if info, ok := shared.Operators[quad.Operator]; ok && info.Right == shared.OperandAddress {
+		return fmt.Sprintf("%s = %s %s %s", name(quad.Result), name(quad.LeftOp), quad.Operator, name(quad.RightOp))
+	}
+	return fmt.Sprintf("%s %v %v %v", quad.Operator, quad.LeftOp, quad.RightOp, quad.Result)
+}
+
+// WriteBlocks writes g as text: the blocks of every function with their
+// quads and the blocks they continue to.
+func WriteBlocks(w io.Writer, g *ControlFlowGraph, names *AddressNames) error {
+	out := bufio.NewWriter(w)
+	for _, function := range g.functions() {
+		fmt.Fprintln(out, function)
+		for _, block := range g.blocksOf(function) {
+			successors := make([]string, len(block.Successors))
+			for i, succ := range block.Successors {
+				successors[i] = strconv.Itoa(succ)
+			}
+			fmt.Fprintf(out, "  block %d -> [%s]\n", block.Index, strings.Join(successors, ", "))
+			for q := block.Start; q < block.End; q++ {
+				fmt.Fprintf(out, "    %d: %s\n", q, names.Label(function, g.Quads[q]))
+			}
+		}
+	}
+	return out.Flush()
+}
+
+// WriteDot writes g in the DOT language of Graphviz: a cluster per function
+// holding its blocks, labeled with their quads. Jumps taken by a gotof are
+// labeled false, calls are dashed edges to the entry of the callee.
+func WriteDot(w io.Writer, g *ControlFlowGraph, names *AddressNames) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, "digraph cfg {")
+	fmt.Fprintln(out, `  node [shape=box, fontname="monospace"];`)
+
+	for i, function := range g.functions() {
+		fmt.Fprintf(out, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(out, "    label=%s;\n", strconv.Quote(function))
+		for _, block := range g.blocksOf(function) {
+			var text strings.Builder
+			for q := block.Start; q < block.End; q++ {
+				fmt.Fprintf(&text, "%d: %s\\l", q, dotEscape(names.Label(function, g.Quads[q])))
+			}
+			fmt.Fprintf(out, "    b%d [label=\"%s\"];\n", block.Index, text.String())
+		}
+		fmt.Fprintln(out, "  }")
+	}
+
+	for _, block := range g.Blocks {
+		last := g.Last(block)
+		for _, succ := range block.Successors {
+			attributes := ""
+			if last.Operator == "gotof" && g.Blocks[succ].Start == last.Result.(int) && block.End != last.Result.(int) {
+				attributes = ` [label="false"]`
+			}
+			fmt.Fprintf(out, "  b%d -> b%d%s;\n", block.Index, succ, attributes)
+		}
+		if last.Operator == "gosub" {
+			if entry, ok := g.Entries[last.LeftOp.(string)]; ok {
+				fmt.Fprintf(out, "  b%d -> b%d [style=dashed, label=\"call\"];\n", block.Index, entry)
+			}
+		}
+	}
+
+	fmt.Fprintln(out, "}")
+	return out.Flush()
+}
+
+// functions returns the program body and the functions in the order their
+// code starts.
+func (g *ControlFlowGraph) functions() []string {
+	functions := make([]string, 0, len(g.Entries))
+	for function := range g.Entries {
+		functions = append(functions, function)
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		return g.Entries[functions[i]] < g.Entries[functions[j]]
+	})
+	return functions
+}
+
+func (g *ControlFlowGraph) blocksOf(function string) []*BasicBlock {
+	blocks := make([]*BasicBlock, 0)
+	for _, block := range g.Blocks {
+		if block.Function == function {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// dotEscape escapes a label line for a double quoted DOT string.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
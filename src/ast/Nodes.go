@@ -0,0 +1,248 @@
+// Package ast declares the syntax tree the parser builds for a Pogo file.
+// Nodes only record what was written and where, names are resolved and
+// types checked when code is generated from the tree.
+package ast
+
+import "pogo/src/shared"
+
+// Pos is where a node starts in the source, lines and columns count from one.
+type Pos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Position returns the position, every node embeds a Pos.
+func (p Pos) Position() Pos {
+	return p
+}
+
+type Node interface {
+	Position() Pos
+}
+
+// Decl is a declaration of the global section: a *VarDecl, *ConstDecl or
+// *TypeDecl.
+type Decl interface {
+	Node
+	declNode()
+}
+
+// Stmt is an item of a block, var and const declarations inside blocks are
+// statements too.
+type Stmt interface {
+	Node
+	stmtNode()
+}
+
+// Expr is an expression. A Name can also stand for a struct value, which is
+// only valid where a struct is expected.
+type Expr interface {
+	Node
+	exprNode()
+}
+
+// Program is a parsed file, a program or a module when Module is set.
+type Program struct {
+	Pos
+	Module  bool        `json:"module"`
+	Name    *Ident      `json:"name"`
+	Imports []*Import   `json:"imports"`
+	Decls   []Decl      `json:"decls"`
+	Funcs   []*FuncDecl `json:"funcs"`
+	Main    *Block      `json:"main"` // the main section, nil in a module
+}
+
+type Import struct {
+	Pos
+	Path string `json:"path"` // relative to the importing file, without quotes
+}
+
+// TypeName is the type of a variable, a parameter or a field: int, float or
+// the name of a struct type.
+type TypeName struct {
+	Pos
+	Name   string `json:"name"`
+	Struct bool   `json:"struct"`
+}
+
+type Ident struct {
+	Pos
+	Name string `json:"name"`
+}
+
+// Declarations
+
+type VarDecl struct {
+	Pos
+	Groups []*VarGroup `json:"groups"`
+}
+
+// VarGroup declares variables of the same type, "a, b : int = 1". The
+// initializer is evaluated once, before the variables are declared.
+type VarGroup struct {
+	Pos
+	Names []*Ident  `json:"names"`
+	Type  *TypeName `json:"type"`
+	Value Expr      `json:"value"` // nil without initializer
+}
+
+type ConstDecl struct {
+	Pos
+	Specs []*ConstSpec `json:"specs"`
+}
+
+type ConstSpec struct {
+	Pos
+	Name  *Ident    `json:"name"`
+	Type  *TypeName `json:"type"`
+	Value Expr      `json:"value"`
+}
+
+// TypeDecl declares a struct type.
+type TypeDecl struct {
+	Pos
+	Name   *Ident   `json:"name"`
+	Fields []*Field `json:"fields"`
+}
+
+type Field struct {
+	Pos
+	Names []*Ident  `json:"names"`
+	Type  *TypeName `json:"type"`
+}
+
+type FuncDecl struct {
+	Pos
+	Name   *Ident   `json:"name"`
+	Params []*Param `json:"params"`
+	Body   *Block   `json:"body"`
+	End    Pos      `json:"end"` // the terminator after the body
+}
+
+type Param struct {
+	Pos
+	Ref  bool      `json:"ref"`
+	Name *Ident    `json:"name"`
+	Type *TypeName `json:"type"`
+}
+
+// Statements
+
+// Block is a list of statements between braces, or between begin and end
+// for the main section.
+type Block struct {
+	Pos
+	Stmts []Stmt `json:"stmts"`
+	End   Pos    `json:"end"` // the closing brace or end
+}
+
+// AssignStmt assigns an expression, or copies a struct value when Target
+// is a struct.
+type AssignStmt struct {
+	Pos
+	Target *Name `json:"target"`
+	Value  Expr  `json:"value"`
+}
+
+// CallStmt calls a function, or a builtin discarding its result.
+type CallStmt struct {
+	Pos
+	Call *CallExpr `json:"call"`
+}
+
+// PrintStmt prints expressions and string literals.
+type PrintStmt struct {
+	Pos
+	Items []Expr `json:"items"`
+}
+
+type IfStmt struct {
+	Pos
+	Cond Expr   `json:"cond"`
+	Then *Block `json:"then"`
+	Else Stmt   `json:"else"` // nil, a *Block or the *IfStmt of an else if
+}
+
+type WhileStmt struct {
+	Pos
+	Cond Expr   `json:"cond"`
+	Body *Block `json:"body"`
+}
+
+type SwitchStmt struct {
+	Pos
+	Tag     Expr          `json:"tag"`
+	Cases   []*CaseClause `json:"cases"`
+	Default *CaseClause   `json:"default"` // nil without default
+}
+
+// CaseClause is a case of a switch, the default clause has no values.
+type CaseClause struct {
+	Pos
+	Values []Expr `json:"values"` // constant expressions
+	Body   []Stmt `json:"body"`
+}
+
+// Expressions
+
+// Name is a variable, a field like p.origin.x or a global of an imported
+// module like utils.count.
+type Name struct {
+	Pos
+	Name string `json:"name"`
+}
+
+// BasicLit is an int, float or string literal as written, strings keep
+// their quotes.
+type BasicLit struct {
+	Pos
+	Type  shared.Type `json:"type"`
+	Value string      `json:"value"`
+}
+
+type UnaryExpr struct {
+	Pos
+	Op string `json:"op"`
+	X  Expr   `json:"x"`
+}
+
+type BinaryExpr struct {
+	Pos        // the operator
+	Op  string `json:"op"`
+	X   Expr   `json:"x"`
+	Y   Expr   `json:"y"`
+}
+
+type ParenExpr struct {
+	Pos
+	X Expr `json:"x"`
+}
+
+// CallExpr calls a builtin or a function, or converts a value with int,
+// float, round, floor or ceil.
+type CallExpr struct {
+	Pos
+	Func *Ident `json:"func"`
+	Args []Expr `json:"args"`
+}
+
+func (*VarDecl) declNode()   {}
+func (*ConstDecl) declNode() {}
+func (*TypeDecl) declNode()  {}
+
+func (*VarDecl) stmtNode()    {}
+func (*ConstDecl) stmtNode()  {}
+func (*Block) stmtNode()      {}
+func (*AssignStmt) stmtNode() {}
+func (*CallStmt) stmtNode()   {}
+func (*PrintStmt) stmtNode()  {}
+func (*IfStmt) stmtNode()     {}
+func (*WhileStmt) stmtNode()  {}
+func (*SwitchStmt) stmtNode() {}
+
+func (*Name) exprNode()       {}
+func (*BasicLit) exprNode()   {}
+func (*UnaryExpr) exprNode()  {}
+func (*BinaryExpr) exprNode() {}
+func (*ParenExpr) exprNode()  {}
+func (*CallExpr) exprNode()   {}
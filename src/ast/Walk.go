@@ -0,0 +1,120 @@
+package ast
+
+// Inspect calls f for node and, as long as f returns true, for each of its
+// children depth first, in source order.
+func Inspect(node Node, f func(Node) bool) {
+	if !f(node) {
+		return
+	}
+	for _, child := range Children(node) {
+		Inspect(child, f)
+	}
+}
+
+// Children returns the nodes directly under node in source order, optional
+// parts that are missing are left out.
+func Children(node Node) []Node {
+	children := make([]Node, 0)
+	add := func(nodes ...Node) {
+		children = append(children, nodes...)
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		add(n.Name)
+		for _, imp := range n.Imports {
+			add(imp)
+		}
+		for _, decl := range n.Decls {
+			add(decl)
+		}
+		for _, fn := range n.Funcs {
+			add(fn)
+		}
+		if n.Main != nil {
+			add(n.Main)
+		}
+	case *VarDecl:
+		for _, group := range n.Groups {
+			add(group)
+		}
+	case *VarGroup:
+		for _, name := range n.Names {
+			add(name)
+		}
+		add(n.Type)
+		if n.Value != nil {
+			add(n.Value)
+		}
+	case *ConstDecl:
+		for _, spec := range n.Specs {
+			add(spec)
+		}
+	case *ConstSpec:
+		add(n.Name, n.Type, n.Value)
+	case *TypeDecl:
+		add(n.Name)
+		for _, field := range n.Fields {
+			add(field)
+		}
+	case *Field:
+		for _, name := range n.Names {
+			add(name)
+		}
+		add(n.Type)
+	case *FuncDecl:
+		add(n.Name)
+		for _, param := range n.Params {
+			add(param)
+		}
+		add(n.Body)
+	case *Param:
+		add(n.Name, n.Type)
+	case *Block:
+		for _, stmt := range n.Stmts {
+			add(stmt)
+		}
+	case *AssignStmt:
+		add(n.Target, n.Value)
+	case *CallStmt:
+		add(n.Call)
+	case *PrintStmt:
+		for _, item := range n.Items {
+			add(item)
+		}
+	case *IfStmt:
+		add(n.Cond, n.Then)
+		if n.Else != nil {
+			add(n.Else)
+		}
+	case *WhileStmt:
+		add(n.Cond, n.Body)
+	case *SwitchStmt:
+		add(n.Tag)
+		for _, clause := range n.Cases {
+			add(clause)
+		}
+		if n.Default != nil {
+			add(n.Default)
+		}
+	case *CaseClause:
+		for _, value := range n.Values {
+			add(value)
+		}
+		for _, stmt := range n.Body {
+			add(stmt)
+		}
+	case *UnaryExpr:
+		add(n.X)
+	case *BinaryExpr:
+		add(n.X, n.Y)
+	case *ParenExpr:
+		add(n.X)
+	case *CallExpr:
+		add(n.Func)
+		for _, arg := range n.Args {
+			add(arg)
+		}
+	}
+	return children
+}
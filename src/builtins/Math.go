@@ -0,0 +1,70 @@
+package builtins
+
+import (
+	"fmt"
+	"math"
+	"pogo/src/shared"
+)
+
+var (
+	floatToFloat   = []Signature{{Parameters: []shared.Type{shared.TypeFloat}, Result: shared.TypeFloat}}
+	numberToNumber = []Signature{
+		{Parameters: []shared.Type{shared.TypeInt}, Result: shared.TypeInt},
+		{Parameters: []shared.Type{shared.TypeFloat}, Result: shared.TypeFloat},
+	}
+	twoNumbers = []Signature{
+		{Parameters: []shared.Type{shared.TypeInt, shared.TypeInt}, Result: shared.TypeInt},
+		{Parameters: []shared.Type{shared.TypeFloat, shared.TypeFloat}, Result: shared.TypeFloat},
+	}
+)
+
+// The standard math library, available to every program.
+func init() {
+	MustRegister(Builtin{Name: "sqrt", Signatures: floatToFloat, Impl: func(args []interface{}) (interface{}, error) {
+		x := args[0].(float64)
+		if x < 0 {
+			return nil, fmt.Errorf("square root of negative number %v", x)
+		}
+		return math.Sqrt(x), nil
+	}})
+
+	MustRegister(Builtin{Name: "sin", Signatures: floatToFloat, Impl: func(args []interface{}) (interface{}, error) {
+		return math.Sin(args[0].(float64)), nil
+	}})
+
+	MustRegister(Builtin{Name: "cos", Signatures: floatToFloat, Impl: func(args []interface{}) (interface{}, error) {
+		return math.Cos(args[0].(float64)), nil
+	}})
+
+	MustRegister(Builtin{
+		Name:       "pow",
+		Signatures: []Signature{{Parameters: []shared.Type{shared.TypeFloat, shared.TypeFloat}, Result: shared.TypeFloat}},
+		Impl: func(args []interface{}) (interface{}, error) {
+			return math.Pow(args[0].(float64), args[1].(float64)), nil
+		},
+	})
+
+	MustRegister(Builtin{Name: "abs", Signatures: numberToNumber, Impl: func(args []interface{}) (interface{}, error) {
+		if x, ok := args[0].(int); ok {
+			if x < 0 {
+				return -x, nil
+			}
+			return x, nil
+		}
+		return math.Abs(args[0].(float64)), nil
+	}})
+
+	MustRegister(Builtin{Name: "min", Signatures: twoNumbers, Impl: func(args []interface{}) (interface{}, error) {
+		if a, ok := args[0].(int); ok {
+			return min(a, args[1].(int)), nil
+		}
+		return math.Min(args[0].(float64), args[1].(float64)), nil
+	}})
+
+	MustRegister(Builtin{Name: "max", Signatures: twoNumbers, Impl: func(args []interface{}) (interface{}, error) {
+		if a, ok := args[0].(int); ok {
+			return max(a, args[1].(int)), nil
+		}
+		return math.Max(args[0].(float64), args[1].(float64)), nil
+	}})
+}
@@ -0,0 +1,158 @@
+package builtins
+
+import (
+	"fmt"
+	"pogo/src/shared"
+	"sort"
+	"sync"
+)
+
+// Func is the Go implementation of a builtin. It receives the arguments
+// already converted to the parameter types of the matched signature (int or
+// float64) and returns an int, a float64, or nil for void builtins.
+type Func func(args []interface{}) (interface{}, error)
+
+// Signature is one accepted parameter list of a builtin and the type of the
+// value it returns, shared.TypeVoid when it returns nothing.
+type Signature struct {
+	Parameters []shared.Type
+	Result     shared.Type
+}
+
+// Builtin is a function implemented in Go that Pogo programs can call.
+// When a call matches more than one signature the first exact match wins,
+// otherwise the first one the arguments can be promoted to (int to float).
+type Builtin struct {
+	Name       string
+	Signatures []Signature
+	Impl       Func
+}
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]Builtin)
+)
+
+// Register adds a builtin to the registry. Builtins must be registered
+// before compiling the programs that call them and before running them.
+func Register(b Builtin) error {
+	if b.Name == "" {
+		return fmt.Errorf("builtin without a name")
+	}
+	if b.Impl == nil {
+		return fmt.Errorf("builtin '%s' has no implementation", b.Name)
+	}
+	if len(b.Signatures) == 0 {
+		return fmt.Errorf("builtin '%s' has no signatures", b.Name)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, exists := registry[b.Name]; exists {
+		return fmt.Errorf("builtin '%s' already registered", b.Name)
+	}
+	registry[b.Name] = b
+	return nil
+}
+
+// MustRegister is like Register but panics on error, it is meant for
+// package initialization.
+func MustRegister(b Builtin) {
+	if err := Register(b); err != nil {
+		panic(err)
+	}
+}
+
+// Unregister removes the builtin registered under name, it does nothing
+// when there is none. Programs compiled while it was registered can no longer
+// call it.
+func Unregister(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	delete(registry, name)
+}
+
+// Lookup returns the builtin registered under name.
+func Lookup(name string) (Builtin, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	b, exists := registry[name]
+	return b, exists
+}
+
+// Names returns the names of all registered builtins, sorted.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve returns the signature that accepts arguments of the given types.
+func (b Builtin) Resolve(args []shared.Type) (Signature, bool) {
+	for _, exact := range []bool{true, false} {
+		for _, signature := range b.Signatures {
+			if signature.accepts(args, exact) {
+				return signature, true
+			}
+		}
+	}
+	return Signature{}, false
+}
+
+func (s Signature) accepts(args []shared.Type, exact bool) bool {
+	if len(s.Parameters) != len(args) {
+		return false
+	}
+	for i, param := range s.Parameters {
+		if param == args[i] {
+			continue
+		}
+		if exact || param != shared.TypeFloat || args[i] != shared.TypeInt {
+			return false
+		}
+	}
+	return true
+}
+
+// Call runs the builtin with values loaded by the virtual machine.
+func (b Builtin) Call(args []interface{}) (interface{}, error) {
+	types := make([]shared.Type, len(args))
+	for i, arg := range args {
+		switch arg.(type) {
+		case int:
+			types[i] = shared.TypeInt
+		case float64:
+			types[i] = shared.TypeFloat
+		default:
+			return nil, fmt.Errorf("invalid argument type for builtin '%s': %T", b.Name, arg)
+		}
+	}
+
+	signature, ok := b.Resolve(types)
+	if !ok {
+		return nil, fmt.Errorf("no signature of builtin '%s' accepts %v", b.Name, types)
+	}
+
+	converted := make([]interface{}, len(args))
+	for i, arg := range args {
+		converted[i] = arg
+		if signature.Parameters[i] == shared.TypeFloat {
+			converted[i], _ = shared.Convert("float", arg)
+		}
+	}
+
+	result, err := b.Impl(converted)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", b.Name, err)
+	}
+	return result, nil
+}
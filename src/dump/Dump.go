@@ -0,0 +1,273 @@
+// Package dump describes a compiled file as JSON for tools like dashboards
+// and visualizers. A Document has a section for each part that was asked
+// for, the others are left out:
+//
+//	{
+//	  "version": 1,
+//	  "file": "fibo.pogo",
+//	  "ast": {"kind": "Program", "line": 1, "column": 1, ...},
+//	  "symbols": {
+//	    "scopes": [{"name": "global", "symbols": [{"name": "n", "kind": "variable", "type": "int", "address": 0, ...}]}],
+//	    "functions": [{"name": "fib", "startQuad": 3, "intVars": 2, "floatVars": 0, "parameters": [...]}],
+//	    "structs": [{"name": "Point", "fields": [{"name": "x", "type": "float"}]}],
+//	    "constants": [{"address": 12000, "type": "int", "value": 1}]
+//	  },
+//	  "quads": [{"index": 0, "op": "goto", "left": null, "right": null, "result": 5, "line": 0, "column": 0}]
+//	}
+//
+// Every node of the syntax tree is an object with its kind, the name of its
+// type in package ast, its line and column, and its fields under the names
+// of their json tags. The README lists the kinds and their fields. Types of
+// literals are written as names, string literals and string constants
+// without their quotes, optional parts that are missing are null.
+//
+// Lists are sorted: scopes by name with the global scope first, symbols,
+// functions and structs by name, constants by address. Operands of quads are
+// what the operator takes according to shared.Operators: addresses, lists
+// of addresses, quad indexes, plain numbers or function names.
+package dump
+
+import (
+	"pogo/src/lexer"
+	"pogo/src/parser"
+	"pogo/src/semantic"
+	"pogo/src/shared"
+	"pogo/src/virtualmachine"
+	"sort"
+)
+
+// Version is the version of the schema, it changes when a field is renamed
+// or removed.
+const Version = 1
+
+// Sections selects the parts of a file that go in a Document.
+type Sections struct {
+	AST     bool
+	Symbols bool
+	Quads   bool
+}
+
+type Document struct {
+	Version int      `json:"version"`
+	File    string   `json:"file,omitempty"`
+	AST     Node     `json:"ast,omitempty"`
+	Symbols *Symbols `json:"symbols,omitempty"`
+	Quads   []Quad   `json:"quads,omitempty"`
+}
+
+type Symbols struct {
+	Scopes    []Scope    `json:"scopes"`
+	Functions []Function `json:"functions"`
+	Structs   []Struct   `json:"structs"`
+	Constants []Constant `json:"constants"`
+}
+
+// Scope holds the variables and constants declared in a scope of the symbol
+// table, functions and struct types have tables of their own.
+type Scope struct {
+	Name    string   `json:"name"`
+	Symbols []Symbol `json:"symbols"`
+}
+
+// Symbol is a variable, a constant, a parameter or a field of a struct
+// value. A struct value has no address of its own, its fields have.
+type Symbol struct {
+	Name    string   `json:"name"`
+	Kind    string   `json:"kind"` // variable, constant or ref for a parameter passed by reference
+	Type    string   `json:"type"` // int, float or the name of a struct type
+	Address *int     `json:"address"`
+	Line    int      `json:"line"`
+	Column  int      `json:"column"`
+	Fields  []Symbol `json:"fields,omitempty"`
+}
+
+type Function struct {
+	Name       string   `json:"name"`
+	Module     string   `json:"module,omitempty"` // the module declaring an external function
+	StartQuad  int      `json:"startQuad"`        // -1 until the function is linked
+	IntVars    int      `json:"intVars"`
+	FloatVars  int      `json:"floatVars"`
+	Parameters []Symbol `json:"parameters"`
+	Line       int      `json:"line"`
+	Column     int      `json:"column"`
+}
+
+type Struct struct {
+	Name   string  `json:"name"`
+	Module string  `json:"module,omitempty"`
+	Fields []Field `json:"fields"`
+	Line   int     `json:"line"`
+	Column int     `json:"column"`
+}
+
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Constant is a value of the constant pool, a number or a string literal.
+type Constant struct {
+	Address int         `json:"address"`
+	Type    string      `json:"type"`
+	Value   interface{} `json:"value"`
+}
+
+type Quad struct {
+	Index  int         `json:"index"`
+	Op     string      `json:"op"`
+	Left   interface{} `json:"left"`
+	Right  interface{} `json:"right"`
+	Result interface{} `json:"result"`
+	Line   int         `json:"line"`
+	Column int         `json:"column"`
+}
+
+// Source compiles a program or a module and describes it. Imports are read
+// from the disk relative to filename.
+func Source(src []byte, filename string, sections Sections) (*Document, error) {
+	p := parser.NewParser(lexer.NewLexer(src))
+	p.Filename = filename
+
+	if _, err := p.Parse(); err != nil {
+		return nil, err
+	}
+	return Build(p, sections), nil
+}
+
+// Build describes a file p has compiled without errors.
+func Build(p *parser.Parser, sections Sections) *Document {
+	doc := &Document{Version: Version, File: p.Filename}
+	if sections.AST {
+		doc.AST = tree(p.Tree())
+	}
+	if sections.Symbols {
+		doc.Symbols = symbols(p.SymbolTable, p.CodeGenerator.MemoryManager)
+	}
+	if sections.Quads {
+		doc.Quads = quads(p.CodeGenerator.Quads)
+	}
+	return doc
+}
+
+func symbols(st *semantic.SymbolTable, memory *virtualmachine.MemoryManager) *Symbols {
+	s := &Symbols{
+		Scopes:    make([]Scope, 0),
+		Functions: make([]Function, 0),
+		Structs:   make([]Struct, 0),
+		Constants: make([]Constant, 0),
+	}
+
+	for name, entries := range st.GetScopes() {
+		scope := Scope{Name: name, Symbols: make([]Symbol, 0)}
+		for _, entry := range entries {
+			switch e := entry.(type) {
+			case shared.Variable:
+				scope.Symbols = append(scope.Symbols, symbol(e))
+			case shared.Function:
+				s.Functions = append(s.Functions, function(e))
+			case shared.StructDef:
+				s.Structs = append(s.Structs, structDef(e))
+			}
+		}
+		sort.Slice(scope.Symbols, func(i, j int) bool { return scope.Symbols[i].Name < scope.Symbols[j].Name })
+		s.Scopes = append(s.Scopes, scope)
+	}
+
+	for offset, value := range memory.ConstantMapLoad {
+		constant := Constant{Address: offset + virtualmachine.CONSTANT_START, Value: value}
+		switch v := value.(type) {
+		case int:
+			constant.Type = shared.TypeInt.String()
+		case float64:
+			constant.Type = shared.TypeFloat.String()
+		case string:
+			constant.Type = shared.TypeString.String()
+			constant.Value = unquote(v)
+		}
+		s.Constants = append(s.Constants, constant)
+	}
+
+	sort.Slice(s.Scopes, func(i, j int) bool {
+		if s.Scopes[i].Name == "global" || s.Scopes[j].Name == "global" {
+			return s.Scopes[i].Name == "global"
+		}
+		return s.Scopes[i].Name < s.Scopes[j].Name
+	})
+	sort.Slice(s.Functions, func(i, j int) bool { return s.Functions[i].Name < s.Functions[j].Name })
+	sort.Slice(s.Structs, func(i, j int) bool { return s.Structs[i].Name < s.Structs[j].Name })
+	sort.Slice(s.Constants, func(i, j int) bool { return s.Constants[i].Address < s.Constants[j].Address })
+	return s
+}
+
+func symbol(variable shared.Variable) Symbol {
+	s := Symbol{
+		Name:   variable.Name,
+		Kind:   "variable",
+		Type:   variable.Type.String(),
+		Line:   variable.Line,
+		Column: variable.Column,
+	}
+	switch {
+	case variable.Constant:
+		s.Kind = "constant"
+	case variable.Ref:
+		s.Kind = "ref"
+	}
+
+	if variable.Type != shared.TypeStruct {
+		address := variable.Address
+		s.Address = &address
+		return s
+	}
+
+	s.Type = variable.Struct
+	s.Fields = make([]Symbol, len(variable.Fields))
+	for i, field := range variable.Fields {
+		s.Fields[i] = symbol(field)
+	}
+	return s
+}
+
+func function(f shared.Function) Function {
+	params := make([]Symbol, len(f.Parameters))
+	for i, param := range f.Parameters {
+		params[i] = symbol(param)
+	}
+	return Function{
+		Name:       f.Name,
+		Module:     f.Module,
+		StartQuad:  f.StartQuad,
+		IntVars:    f.IntVarsCounter,
+		FloatVars:  f.FloatVarsCounter,
+		Parameters: params,
+		Line:       f.Line,
+		Column:     f.Column,
+	}
+}
+
+func structDef(def shared.StructDef) Struct {
+	fields := make([]Field, len(def.Fields))
+	for i, field := range def.Fields {
+		fields[i] = Field{Name: field.Name, Type: field.Type.String()}
+		if field.Type == shared.TypeStruct {
+			fields[i].Type = field.Struct
+		}
+	}
+	return Struct{Name: def.Name, Module: def.Module, Fields: fields, Line: def.Line, Column: def.Column}
+}
+
+func quads(list []shared.Quadruple) []Quad {
+	out := make([]Quad, len(list))
+	for i, quad := range list {
+		out[i] = Quad{
+			Index:  i,
+			Op:     quad.Operator,
+			Left:   quad.LeftOp,
+			Right:  quad.RightOp,
+			Result: quad.Result,
+			Line:   quad.Line,
+			Column: quad.Column,
+		}
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package dump
+
+import (
+	"pogo/src/ast"
+	"pogo/src/shared"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Node is a node of the syntax tree, see the package documentation.
+type Node map[string]interface{}
+
+// tree describes node and the nodes under it by the json tags of their
+// fields, fields without a tag are left out.
+func tree(node ast.Node) Node {
+	v := reflect.ValueOf(node).Elem()
+	t := v.Type()
+
+	out := Node{"kind": t.Name()}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			pos := v.Field(i).Interface().(ast.Pos)
+			out["line"], out["column"] = pos.Line, pos.Column
+			continue
+		}
+		name := field.Tag.Get("json")
+		if name == "" || name == "-" {
+			continue
+		}
+		out[name] = value(v.Field(i))
+	}
+
+	if lit, ok := node.(*ast.BasicLit); ok && lit.Type == shared.TypeString {
+		out["value"] = unquote(lit.Value)
+	}
+	return out
+}
+
+func value(v reflect.Value) interface{} {
+	switch x := v.Interface().(type) {
+	case ast.Pos:
+		return x
+	case shared.Type:
+		return x.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return tree(v.Interface().(ast.Node))
+	case reflect.Slice:
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = value(v.Index(i))
+		}
+		return items
+	}
+	return v.Interface()
+}
+
+// unquote returns the text of a string literal. Escapes Go doesn't know are
+// kept as written.
+func unquote(lit string) string {
+	if text, err := strconv.Unquote(lit); err == nil {
+		return text
+	}
+	return strings.Trim(lit, "\"`")
+}
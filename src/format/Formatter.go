@@ -0,0 +1,257 @@
+// Package format rewrites Pogo source in its canonical layout: one statement
+// per line, four spaces of indentation per block and single spaces around
+// binary operators. Comments are kept where they are, and so are single
+// blank lines between statements.
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"pogo/src/lexer"
+	"pogo/src/token"
+	"strings"
+)
+
+type blockKind int
+
+const (
+	plainBlock blockKind = iota
+	structBlock
+	switchBlock
+	mainBlock // begin ... end
+)
+
+type block struct {
+	kind   blockKind
+	inCase bool // a switch block is in the statements of a case
+}
+
+type printer struct {
+	out    bytes.Buffer
+	blocks []block
+	parens int
+
+	prev *token.Token // the last token printed, comments included
+	last *token.Token // the last token printed that isn't a comment
+
+	nextBlock    blockKind // the kind of the next '{', after struct or switch
+	caseLabel    bool      // between case or default and its ':'
+	endsLabel    bool      // last is the ':' of a case label
+	unary        bool      // last is a sign, not a binary operator
+	closedStruct bool      // last is the '}' of a struct type
+}
+
+// Source formats a program or a module. It only needs the source to be made
+// of valid tokens with balanced blocks, formatting it again gives the same
+// result.
+func Source(src []byte) ([]byte, error) {
+	p := &printer{}
+
+	for _, tok := range lexer.NewLexer(src).ScanLossless() {
+		switch tok.Type {
+		case lexer.Whitespace:
+		case lexer.Comment:
+			p.comment(tok)
+		case token.INVALID:
+			return nil, fmt.Errorf("line %d: invalid token '%s'", tok.Line, tok.Lit)
+		case token.EOF:
+			if len(p.blocks) > 0 {
+				return nil, fmt.Errorf("line %d: unexpected end of file, a block is not closed", tok.Line)
+			}
+		default:
+			if err := p.token(tok); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if p.prev != nil {
+		p.out.WriteByte('\n')
+	}
+	return p.out.Bytes(), nil
+}
+
+func (p *printer) token(tok *token.Token) error {
+	// The terminator after a struct type is optional, the canonical form
+	// leaves it out
+	if p.closedStruct && is(tok, "terminator") {
+		p.closedStruct = false
+		return nil
+	}
+	p.closedStruct = false
+
+	newline := p.breaksBefore(tok)
+
+	switch {
+	case is(tok, "closeBrace"):
+		if len(p.blocks) == 0 || p.top().kind == mainBlock {
+			return fmt.Errorf("line %d: unexpected '}'", tok.Line)
+		}
+		p.closedStruct = p.top().kind == structBlock
+		p.blocks = p.blocks[:len(p.blocks)-1]
+	case is(tok, "kwdEnd"):
+		if len(p.blocks) == 0 || p.top().kind != mainBlock {
+			return fmt.Errorf("line %d: unexpected 'end'", tok.Line)
+		}
+		p.blocks = p.blocks[:len(p.blocks)-1]
+	case is(tok, "kwdCase", "kwdDefault"):
+		if len(p.blocks) > 0 {
+			p.top().inCase = false
+		}
+		p.caseLabel = true
+	}
+
+	p.separate(tok, newline)
+	p.out.Write(tok.Lit)
+
+	p.unary = is(tok, "expressionOp") && (p.last == nil || is(p.last, "openParan", "repeatTerminator",
+		"assignOp", "relOp", "expressionOp", "termOp", "powOp", "kwdCase"))
+	p.endsLabel = false
+
+	switch {
+	case is(tok, "openBrace"):
+		p.blocks = append(p.blocks, block{kind: p.nextBlock})
+		p.nextBlock = plainBlock
+	case is(tok, "kwdBegin"):
+		p.blocks = append(p.blocks, block{kind: mainBlock})
+	case is(tok, "kwdStruct"):
+		p.nextBlock = structBlock
+	case is(tok, "kwdSwitch"):
+		p.nextBlock = switchBlock
+	case is(tok, "openParan"):
+		p.parens++
+	case is(tok, "closeParan"):
+		p.parens--
+	case is(tok, "typeAssignOp") && p.caseLabel:
+		p.caseLabel = false
+		p.endsLabel = true
+		if len(p.blocks) > 0 && p.top().kind == switchBlock {
+			p.top().inCase = true
+		}
+	}
+
+	p.prev, p.last = tok, tok
+	return nil
+}
+
+// comment prints a comment on the line of the token before it when it was
+// there in the source, otherwise on a line of its own.
+func (p *printer) comment(tok *token.Token) {
+	switch {
+	case p.prev == nil:
+	case tok.Line == endLine(p.prev) && !isLineComment(p.prev):
+		p.out.WriteByte(' ')
+	default:
+		p.newline(tok.Line > endLine(p.prev)+1 && !p.opensLine())
+	}
+
+	p.out.Write(bytes.TrimRight(tok.Lit, " \t\r\n"))
+	p.prev = tok
+}
+
+// separate writes what goes between the last token printed and tok.
+func (p *printer) separate(tok *token.Token, newline bool) {
+	if p.prev == nil {
+		return
+	}
+	closing := is(tok, "closeBrace", "kwdEnd")
+
+	if p.prev.Type == lexer.Comment {
+		if newline || isLineComment(p.prev) || tok.Line > endLine(p.prev) {
+			p.newline(tok.Line > endLine(p.prev)+1 && !closing)
+		} else if !is(tok, "repeatTerminator", "terminator", "closeParan") {
+			p.out.WriteByte(' ')
+		}
+		return
+	}
+
+	if newline {
+		p.newline(tok.Line > endLine(p.prev)+1 && !closing && !p.opensLine())
+	} else if p.spaced(tok) {
+		p.out.WriteByte(' ')
+	}
+}
+
+// breaksBefore tells whether tok starts a new line: it starts a statement,
+// a declaration or a case, or it closes a block.
+func (p *printer) breaksBefore(tok *token.Token) bool {
+	last := p.last
+	switch {
+	case last == nil:
+		return false
+	case is(tok, "closeBrace", "kwdEnd", "kwdCase", "kwdDefault"):
+		return true
+	case is(last, "closeBrace"):
+		return !is(tok, "kwdElse", "terminator")
+	case is(last, "terminator", "openBrace", "kwdBegin") || p.endsLabel:
+		return true
+	case is(last, "closeParan") && p.parens == 0:
+		// Calls and prints have no terminator, the statement after them
+		// starts right after their closing parenthesis
+		return is(tok, "id", "kwdPrint", "kwdIf", "kwdWhile", "kwdSwitch", "kwdVars", "kwdConst")
+	}
+	return false
+}
+
+// spaced tells whether a space goes between the last token and tok when
+// they are on the same line.
+func (p *printer) spaced(tok *token.Token) bool {
+	last := p.last
+	switch {
+	case is(tok, "repeatTerminator", "terminator", "closeParan", "dot"):
+		return false
+	case is(last, "openParan", "dot") || p.unary:
+		return false
+	case is(tok, "openParan"):
+		// calls, conversions and print
+		return !is(last, "id", "type", "kwdPrint")
+	case is(tok, "typeAssignOp"):
+		return !p.caseLabel
+	}
+	return true
+}
+
+// opensLine tells whether the last token opens a block or a case, no blank
+// line goes after them.
+func (p *printer) opensLine() bool {
+	return p.prev == p.last && (is(p.last, "openBrace", "kwdBegin") || p.endsLabel)
+}
+
+func (p *printer) newline(blank bool) {
+	p.out.WriteByte('\n')
+	if blank {
+		p.out.WriteByte('\n')
+	}
+
+	indent := 0
+	for _, b := range p.blocks {
+		indent++
+		if b.inCase {
+			indent++
+		}
+	}
+	p.out.WriteString(strings.Repeat("    ", indent))
+}
+
+func (p *printer) top() *block {
+	return &p.blocks[len(p.blocks)-1]
+}
+
+func is(tok *token.Token, types ...string) bool {
+	for _, typ := range types {
+		if tok.Type == token.TokMap.Type(typ) {
+			return true
+		}
+	}
+	return false
+}
+
+func isLineComment(tok *token.Token) bool {
+	return tok.Type == lexer.Comment && bytes.HasPrefix(tok.Lit, []byte("//"))
+}
+
+// endLine is the line a token ends on, comments and raw strings can span
+// several lines.
+func endLine(tok *token.Token) int {
+	return tok.Line + bytes.Count(bytes.TrimRight(tok.Lit, " \t\r\n"), []byte("\n"))
+}
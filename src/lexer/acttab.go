@@ -29,127 +29,127 @@ var ActTab = ActionTable{
 		Ignore: "!whitespace",
 	},
 	ActionRow{ // S2
-		Accept: 0,
-		Ignore: "",
+		Accept: -1,
+		Ignore: "!whitespace",
 	},
 	ActionRow{ // S3
-		Accept: 0,
-		Ignore: "",
+		Accept: -1,
+		Ignore: "!whitespace",
 	},
 	ActionRow{ // S4
-		Accept: 19,
-		Ignore: "",
+		Accept: -1,
+		Ignore: "!whitespace",
 	},
 	ActionRow{ // S5
-		Accept: 4,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S6
-		Accept: 23,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S7
-		Accept: 5,
+		Accept: 34,
 		Ignore: "",
 	},
 	ActionRow{ // S8
-		Accept: 21,
+		Accept: 29,
 		Ignore: "",
 	},
 	ActionRow{ // S9
-		Accept: 23,
+		Accept: 4,
 		Ignore: "",
 	},
 	ActionRow{ // S10
-		Accept: 8,
+		Accept: 34,
 		Ignore: "",
 	},
 	ActionRow{ // S11
-		Accept: 8,
+		Accept: 6,
 		Ignore: "",
 	},
 	ActionRow{ // S12
-		Accept: 26,
+		Accept: 32,
 		Ignore: "",
 	},
 	ActionRow{ // S13
-		Accept: 24,
+		Accept: 6,
 		Ignore: "",
 	},
 	ActionRow{ // S14
-		Accept: 20,
+		Accept: 5,
 		Ignore: "",
 	},
 	ActionRow{ // S15
-		Accept: 2,
+		Accept: 34,
 		Ignore: "",
 	},
 	ActionRow{ // S16
-		Accept: 7,
+		Accept: 9,
 		Ignore: "",
 	},
 	ActionRow{ // S17
-		Accept: 7,
+		Accept: 9,
 		Ignore: "",
 	},
 	ActionRow{ // S18
-		Accept: 0,
+		Accept: 37,
 		Ignore: "",
 	},
 	ActionRow{ // S19
-		Accept: 7,
+		Accept: 35,
 		Ignore: "",
 	},
 	ActionRow{ // S20
-		Accept: 7,
+		Accept: 31,
 		Ignore: "",
 	},
 	ActionRow{ // S21
-		Accept: 7,
+		Accept: 2,
 		Ignore: "",
 	},
 	ActionRow{ // S22
-		Accept: 7,
+		Accept: 31,
 		Ignore: "",
 	},
 	ActionRow{ // S23
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S24
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S25
-		Accept: 7,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S26
-		Accept: 18,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S27
-		Accept: 3,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S28
-		Accept: 22,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S29
-		Accept: 0,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S30
-		Accept: 0,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S31
-		Accept: 0,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S32
-		Accept: 0,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S33
@@ -157,55 +157,55 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S34
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S35
-		Accept: 22,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S36
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S37
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S38
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S39
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S40
-		Accept: 7,
+		Accept: 28,
 		Ignore: "",
 	},
 	ActionRow{ // S41
-		Accept: 13,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S42
-		Accept: 7,
+		Accept: 31,
 		Ignore: "",
 	},
 	ActionRow{ // S43
-		Accept: 7,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S44
-		Accept: 7,
+		Accept: 33,
 		Ignore: "",
 	},
 	ActionRow{ // S45
-		Accept: 7,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S46
-		Accept: 0,
+		Accept: 30,
 		Ignore: "",
 	},
 	ActionRow{ // S47
@@ -213,107 +213,375 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S48
-		Accept: -1,
-		Ignore: "!comment",
+		Accept: 0,
+		Ignore: "",
 	},
 	ActionRow{ // S49
-		Accept: 6,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S50
-		Accept: 7,
+		Accept: 9,
 		Ignore: "",
 	},
 	ActionRow{ // S51
-		Accept: 7,
+		Accept: 31,
 		Ignore: "",
 	},
 	ActionRow{ // S52
-		Accept: 11,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S53
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S54
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S55
-		Accept: 25,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S56
-		Accept: 7,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S57
-		Accept: 7,
+		Accept: 33,
 		Ignore: "",
 	},
 	ActionRow{ // S58
-		Accept: 16,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S59
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S60
-		Accept: -1,
-		Ignore: "!comment",
+		Accept: 8,
+		Ignore: "",
 	},
 	ActionRow{ // S61
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S62
-		Accept: 10,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S63
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S64
-		Accept: 12,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S65
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S66
-		Accept: 7,
+		Accept: 17,
 		Ignore: "",
 	},
 	ActionRow{ // S67
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S68
-		Accept: 9,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S69
-		Accept: 14,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S70
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S71
-		Accept: 17,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S72
-		Accept: 7,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S73
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S74
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S75
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S76
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S77
+		Accept: 0,
+		Ignore: "",
+	},
+	ActionRow{ // S78
+		Accept: 0,
+		Ignore: "",
+	},
+	ActionRow{ // S79
+		Accept: 0,
+		Ignore: "",
+	},
+	ActionRow{ // S80
+		Accept: 0,
+		Ignore: "",
+	},
+	ActionRow{ // S81
+		Accept: 0,
+		Ignore: "",
+	},
+	ActionRow{ // S82
+		Accept: 0,
+		Ignore: "",
+	},
+	ActionRow{ // S83
+		Accept: 0,
+		Ignore: "",
+	},
+	ActionRow{ // S84
+		Accept: -1,
+		Ignore: "!comment",
+	},
+	ActionRow{ // S85
+		Accept: 7,
+		Ignore: "",
+	},
+	ActionRow{ // S86
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S87
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S88
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S89
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S90
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S91
 		Accept: 15,
 		Ignore: "",
 	},
+	ActionRow{ // S92
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S93
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S94
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S95
+		Accept: 36,
+		Ignore: "",
+	},
+	ActionRow{ // S96
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S97
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S98
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S99
+		Accept: 22,
+		Ignore: "",
+	},
+	ActionRow{ // S100
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S101
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S102
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S103
+		Accept: 26,
+		Ignore: "",
+	},
+	ActionRow{ // S104
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S105
+		Accept: -1,
+		Ignore: "!comment",
+	},
+	ActionRow{ // S106
+		Accept: 7,
+		Ignore: "",
+	},
+	ActionRow{ // S107
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S108
+		Accept: 11,
+		Ignore: "",
+	},
+	ActionRow{ // S109
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S110
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S111
+		Accept: 14,
+		Ignore: "",
+	},
+	ActionRow{ // S112
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S113
+		Accept: 16,
+		Ignore: "",
+	},
+	ActionRow{ // S114
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S115
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S116
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S117
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S118
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S119
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S120
+		Accept: 25,
+		Ignore: "",
+	},
+	ActionRow{ // S121
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S122
+		Accept: 10,
+		Ignore: "",
+	},
+	ActionRow{ // S123
+		Accept: 12,
+		Ignore: "",
+	},
+	ActionRow{ // S124
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S125
+		Accept: 36,
+		Ignore: "",
+	},
+	ActionRow{ // S126
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S127
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S128
+		Accept: 20,
+		Ignore: "",
+	},
+	ActionRow{ // S129
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S130
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S131
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S132
+		Accept: 27,
+		Ignore: "",
+	},
+	ActionRow{ // S133
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S134
+		Accept: 18,
+		Ignore: "",
+	},
+	ActionRow{ // S135
+		Accept: 19,
+		Ignore: "",
+	},
+	ActionRow{ // S136
+		Accept: 8,
+		Ignore: "",
+	},
+	ActionRow{ // S137
+		Accept: 23,
+		Ignore: "",
+	},
+	ActionRow{ // S138
+		Accept: 24,
+		Ignore: "",
+	},
+	ActionRow{ // S139
+		Accept: 13,
+		Ignore: "",
+	},
+	ActionRow{ // S140
+		Accept: 21,
+		Ignore: "",
+	},
 }
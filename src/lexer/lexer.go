@@ -11,8 +11,8 @@ import (
 
 const (
 	NoState    = -1
-	NumStates  = 74
-	NumSymbols = 96
+	NumStates  = 141
+	NumSymbols = 147
 )
 
 type Lexer struct {
@@ -177,52 +177,103 @@ Lexer symbols:
 45: 'v'
 46: 'a'
 47: 'r'
-48: '='
-49: '='
-50: '!'
-51: '='
-52: '<'
-53: '>'
-54: '+'
-55: '-'
-56: '*'
-57: '/'
-58: '='
-59: ':'
-60: '{'
-61: '}'
-62: '('
-63: ')'
-64: '0'
-65: '.'
-66: '_'
-67: '`'
-68: '`'
-69: '\'
-70: 'n'
-71: '\'
-72: 'r'
-73: '\'
+48: 'c'
+49: 'o'
+50: 'n'
+51: 's'
+52: 't'
+53: 'i'
+54: 'm'
+55: 'p'
+56: 'o'
+57: 'r'
+58: 't'
+59: 'm'
+60: 'o'
+61: 'd'
+62: 'u'
+63: 'l'
+64: 'e'
+65: 't'
+66: 'y'
+67: 'p'
+68: 'e'
+69: 's'
+70: 't'
+71: 'r'
+72: 'u'
+73: 'c'
 74: 't'
-75: '"'
-76: '\'
-77: '"'
-78: '"'
-79: '/'
-80: '/'
-81: '\n'
-82: '/'
-83: '*'
-84: '*'
-85: '*'
-86: '/'
-87: ' '
-88: '\t'
-89: '\n'
-90: '\r'
-91: '1'-'9'
-92: 'a'-'z'
-93: 'A'-'Z'
-94: '0'-'9'
-95: .
+75: 'r'
+76: 'e'
+77: 'f'
+78: 's'
+79: 'w'
+80: 'i'
+81: 't'
+82: 'c'
+83: 'h'
+84: 'c'
+85: 'a'
+86: 's'
+87: 'e'
+88: 'd'
+89: 'e'
+90: 'f'
+91: 'a'
+92: 'u'
+93: 'l'
+94: 't'
+95: '='
+96: '='
+97: '!'
+98: '='
+99: '<'
+100: '>'
+101: '+'
+102: '-'
+103: '*'
+104: '/'
+105: '%'
+106: '*'
+107: '*'
+108: '='
+109: ':'
+110: '{'
+111: '}'
+112: '('
+113: ')'
+114: '.'
+115: '_'
+116: '0'
+117: '.'
+118: '`'
+119: '`'
+120: '\'
+121: 'n'
+122: '\'
+123: 'r'
+124: '\'
+125: 't'
+126: '"'
+127: '\'
+128: '"'
+129: '"'
+130: ' '
+131: '\t'
+132: '\n'
+133: '\r'
+134: '/'
+135: '/'
+136: '\n'
+137: '/'
+138: '*'
+139: '*'
+140: '*'
+141: '/'
+142: 'a'-'z'
+143: 'A'-'Z'
+144: '0'-'9'
+145: '1'-'9'
+146: .
 */
@@ -0,0 +1,94 @@
+package lexer
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"pogo/src/token"
+)
+
+// Types of the tokens ScanLossless returns for the text the lexer suppresses.
+// They come after every type of token.TokMap, which names them "unknown".
+const (
+	Whitespace token.Type = 1<<16 + iota
+	Comment
+)
+
+// ScanLossless returns every token up to and including EOF, with the
+// whitespace and the comments between them, so joining their literals gives
+// back the source. Tools that rewrite the source, like the formatter, use it
+// instead of Scan.
+func (l *Lexer) ScanLossless() []*token.Token {
+	tokens := make([]*token.Token, 0)
+	end, line, column := l.pos, l.line, l.column
+
+	for {
+		tok := l.Scan()
+
+		// Only suppressed text is left between the previous token and this one
+		gap := l.src[end:tok.Offset]
+		for len(gap) > 0 {
+			size, typ := suppressedLength(gap)
+			tokens = append(tokens, &token.Token{
+				Type: typ,
+				Lit:  gap[:size],
+				Pos:  token.Pos{Offset: end, Line: line, Column: column, Context: l.Context},
+			})
+			line, column = advance(line, column, gap[:size])
+			end += size
+			gap = gap[size:]
+		}
+
+		tokens = append(tokens, tok)
+		if tok.Type == token.EOF {
+			return tokens
+		}
+		end = tok.Offset + len(tok.Lit)
+		line, column = advance(tok.Line, tok.Column, tok.Lit)
+	}
+}
+
+// suppressedLength returns the length and the type of the comment or the run
+// of whitespace src starts with.
+func suppressedLength(src []byte) (int, token.Type) {
+	switch {
+	case bytes.HasPrefix(src, []byte("//")):
+		if i := bytes.IndexByte(src, '\n'); i >= 0 {
+			return i + 1, Comment
+		}
+		return len(src), Comment
+	case bytes.HasPrefix(src, []byte("/*")):
+		if i := bytes.Index(src[2:], []byte("*/")); i >= 0 {
+			return i + 4, Comment
+		}
+		return len(src), Comment
+	}
+
+	size := 0
+	for size < len(src) && bytes.IndexByte([]byte(" \t\n\r"), src[size]) >= 0 {
+		size++
+	}
+	if size == 0 {
+		_, size = utf8.DecodeRune(src)
+		return size, token.INVALID
+	}
+	return size, Whitespace
+}
+
+// advance moves a position past text, counting lines and columns as Scan does.
+func advance(line, column int, text []byte) (int, int) {
+	for _, r := range string(text) {
+		switch r {
+		case '\n':
+			line++
+			column = 1
+		case '\r':
+			column = 1
+		case '\t':
+			column += 4
+		default:
+			column++
+		}
+	}
+	return line, column
+}
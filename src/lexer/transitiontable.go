@@ -16,79 +16,97 @@ var TransTab = TransitionTable{
 		case r == 9: // ['\t','\t']
 			return 1
 		case r == 10: // ['\n','\n']
-			return 1
+			return 2
 		case r == 13: // ['\r','\r']
-			return 1
+			return 3
 		case r == 32: // [' ',' ']
-			return 1
+			return 4
 		case r == 33: // ['!','!']
-			return 2
+			return 5
 		case r == 34: // ['"','"']
-			return 3
+			return 6
+		case r == 37: // ['%','%']
+			return 7
 		case r == 40: // ['(','(']
-			return 4
+			return 8
 		case r == 41: // [')',')']
-			return 5
+			return 9
 		case r == 42: // ['*','*']
-			return 6
+			return 10
 		case r == 43: // ['+','+']
-			return 7
+			return 11
 		case r == 44: // [',',',']
-			return 8
+			return 12
 		case r == 45: // ['-','-']
-			return 7
+			return 13
+		case r == 46: // ['.','.']
+			return 14
 		case r == 47: // ['/','/']
-			return 9
+			return 15
 		case r == 48: // ['0','0']
-			return 10
+			return 16
 		case 49 <= r && r <= 57: // ['1','9']
-			return 11
+			return 17
 		case r == 58: // [':',':']
-			return 12
+			return 18
 		case r == 59: // [';',';']
-			return 13
+			return 19
 		case r == 60: // ['<','<']
-			return 14
+			return 20
 		case r == 61: // ['=','=']
-			return 15
+			return 21
 		case r == 62: // ['>','>']
-			return 14
+			return 22
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 23
 		case r == 95: // ['_','_']
-			return 17
+			return 24
 		case r == 96: // ['`','`']
-			return 18
+			return 25
 		case r == 97: // ['a','a']
-			return 16
+			return 26
 		case r == 98: // ['b','b']
-			return 19
-		case 99 <= r && r <= 100: // ['c','d']
-			return 16
+			return 27
+		case r == 99: // ['c','c']
+			return 28
+		case r == 100: // ['d','d']
+			return 29
 		case r == 101: // ['e','e']
-			return 20
+			return 30
 		case r == 102: // ['f','f']
-			return 21
+			return 31
 		case 103 <= r && r <= 104: // ['g','h']
-			return 16
+			return 26
 		case r == 105: // ['i','i']
-			return 22
-		case 106 <= r && r <= 111: // ['j','o']
-			return 16
+			return 32
+		case 106 <= r && r <= 108: // ['j','l']
+			return 26
+		case r == 109: // ['m','m']
+			return 33
+		case 110 <= r && r <= 111: // ['n','o']
+			return 26
 		case r == 112: // ['p','p']
-			return 23
-		case 113 <= r && r <= 117: // ['q','u']
-			return 16
+			return 34
+		case r == 113: // ['q','q']
+			return 26
+		case r == 114: // ['r','r']
+			return 35
+		case r == 115: // ['s','s']
+			return 36
+		case r == 116: // ['t','t']
+			return 37
+		case r == 117: // ['u','u']
+			return 26
 		case r == 118: // ['v','v']
-			return 24
+			return 38
 		case r == 119: // ['w','w']
-			return 25
+			return 39
 		case 120 <= r && r <= 122: // ['x','z']
-			return 16
-		case r == 123: // ['{','{']
 			return 26
+		case r == 123: // ['{','{']
+			return 40
 		case r == 125: // ['}','}']
-			return 27
+			return 41
 		}
 		return NoState
 	},
@@ -101,21 +119,14 @@ var TransTab = TransitionTable{
 	// S2
 	func(r rune) int {
 		switch {
-		case r == 61: // ['=','=']
-			return 14
 		}
 		return NoState
 	},
 	// S3
 	func(r rune) int {
 		switch {
-		case r == 34: // ['"','"']
-			return 28
-		case r == 92: // ['\','\']
-			return 29
-		default:
-			return 3
 		}
+		return NoState
 	},
 	// S4
 	func(r rune) int {
@@ -126,14 +137,21 @@ var TransTab = TransitionTable{
 	// S5
 	func(r rune) int {
 		switch {
+		case r == 61: // ['=','=']
+			return 42
 		}
 		return NoState
 	},
 	// S6
 	func(r rune) int {
 		switch {
+		case r == 34: // ['"','"']
+			return 44
+		case r == 92: // ['\','\']
+			return 45
+		default:
+			return 43
 		}
-		return NoState
 	},
 	// S7
 	func(r rune) int {
@@ -150,28 +168,20 @@ var TransTab = TransitionTable{
 	// S9
 	func(r rune) int {
 		switch {
-		case r == 42: // ['*','*']
-			return 30
-		case r == 47: // ['/','/']
-			return 31
 		}
 		return NoState
 	},
 	// S10
 	func(r rune) int {
 		switch {
-		case r == 46: // ['.','.']
-			return 32
+		case r == 42: // ['*','*']
+			return 46
 		}
 		return NoState
 	},
 	// S11
 	func(r rune) int {
 		switch {
-		case r == 46: // ['.','.']
-			return 32
-		case 48 <= r && r <= 57: // ['0','9']
-			return 33
 		}
 		return NoState
 	},
@@ -196,129 +206,60 @@ var TransTab = TransitionTable{
 	// S15
 	func(r rune) int {
 		switch {
-		case r == 61: // ['=','=']
-			return 14
+		case r == 42: // ['*','*']
+			return 47
+		case r == 47: // ['/','/']
+			return 48
 		}
 		return NoState
 	},
 	// S16
 	func(r rune) int {
 		switch {
-		case 48 <= r && r <= 57: // ['0','9']
-			return 34
-		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
-		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 122: // ['a','z']
-			return 16
+		case r == 46: // ['.','.']
+			return 49
 		}
 		return NoState
 	},
 	// S17
 	func(r rune) int {
 		switch {
+		case r == 46: // ['.','.']
+			return 49
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
-		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
-		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 122: // ['a','z']
-			return 16
+			return 50
 		}
 		return NoState
 	},
 	// S18
 	func(r rune) int {
 		switch {
-		case r == 96: // ['`','`']
-			return 35
-		default:
-			return 18
 		}
+		return NoState
 	},
 	// S19
 	func(r rune) int {
 		switch {
-		case 48 <= r && r <= 57: // ['0','9']
-			return 34
-		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
-		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 100: // ['a','d']
-			return 16
-		case r == 101: // ['e','e']
-			return 36
-		case 102 <= r && r <= 122: // ['f','z']
-			return 16
 		}
 		return NoState
 	},
 	// S20
 	func(r rune) int {
 		switch {
-		case 48 <= r && r <= 57: // ['0','9']
-			return 34
-		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
-		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 107: // ['a','k']
-			return 16
-		case r == 108: // ['l','l']
-			return 37
-		case r == 109: // ['m','m']
-			return 16
-		case r == 110: // ['n','n']
-			return 38
-		case 111 <= r && r <= 122: // ['o','z']
-			return 16
 		}
 		return NoState
 	},
 	// S21
 	func(r rune) int {
 		switch {
-		case 48 <= r && r <= 57: // ['0','9']
-			return 34
-		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
-		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 107: // ['a','k']
-			return 16
-		case r == 108: // ['l','l']
-			return 39
-		case 109 <= r && r <= 116: // ['m','t']
-			return 16
-		case r == 117: // ['u','u']
-			return 40
-		case 118 <= r && r <= 122: // ['v','z']
-			return 16
+		case r == 61: // ['=','=']
+			return 51
 		}
 		return NoState
 	},
 	// S22
 	func(r rune) int {
 		switch {
-		case 48 <= r && r <= 57: // ['0','9']
-			return 34
-		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
-		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 101: // ['a','e']
-			return 16
-		case r == 102: // ['f','f']
-			return 41
-		case 103 <= r && r <= 109: // ['g','m']
-			return 16
-		case r == 110: // ['n','n']
-			return 42
-		case 111 <= r && r <= 122: // ['o','z']
-			return 16
 		}
 		return NoState
 	},
@@ -326,17 +267,13 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 113: // ['a','q']
-			return 16
-		case r == 114: // ['r','r']
-			return 43
-		case 115 <= r && r <= 122: // ['s','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
 		}
 		return NoState
 	},
@@ -344,91 +281,160 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case r == 97: // ['a','a']
-			return 44
-		case 98 <= r && r <= 122: // ['b','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
 		}
 		return NoState
 	},
 	// S25
 	func(r rune) int {
 		switch {
-		case 48 <= r && r <= 57: // ['0','9']
-			return 34
-		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
-		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 103: // ['a','g']
-			return 16
-		case r == 104: // ['h','h']
-			return 45
-		case 105 <= r && r <= 122: // ['i','z']
-			return 16
+		case r == 96: // ['`','`']
+			return 57
+		default:
+			return 56
 		}
-		return NoState
 	},
 	// S26
 	func(r rune) int {
 		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
 		}
 		return NoState
 	},
 	// S27
 	func(r rune) int {
 		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 100: // ['a','d']
+			return 55
+		case r == 101: // ['e','e']
+			return 58
+		case 102 <= r && r <= 122: // ['f','z']
+			return 55
 		}
 		return NoState
 	},
 	// S28
 	func(r rune) int {
 		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case r == 97: // ['a','a']
+			return 59
+		case 98 <= r && r <= 110: // ['b','n']
+			return 55
+		case r == 111: // ['o','o']
+			return 60
+		case 112 <= r && r <= 122: // ['p','z']
+			return 55
 		}
 		return NoState
 	},
 	// S29
 	func(r rune) int {
 		switch {
-		case r == 34: // ['"','"']
-			return 3
-		case r == 110: // ['n','n']
-			return 46
-		case r == 114: // ['r','r']
-			return 46
-		case r == 116: // ['t','t']
-			return 46
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 100: // ['a','d']
+			return 55
+		case r == 101: // ['e','e']
+			return 61
+		case 102 <= r && r <= 122: // ['f','z']
+			return 55
 		}
 		return NoState
 	},
 	// S30
 	func(r rune) int {
 		switch {
-		case r == 42: // ['*','*']
-			return 47
-		default:
-			return 30
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 107: // ['a','k']
+			return 55
+		case r == 108: // ['l','l']
+			return 62
+		case r == 109: // ['m','m']
+			return 55
+		case r == 110: // ['n','n']
+			return 63
+		case 111 <= r && r <= 122: // ['o','z']
+			return 55
 		}
+		return NoState
 	},
 	// S31
 	func(r rune) int {
 		switch {
-		case r == 10: // ['\n','\n']
-			return 48
-		default:
-			return 31
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 107: // ['a','k']
+			return 55
+		case r == 108: // ['l','l']
+			return 64
+		case 109 <= r && r <= 116: // ['m','t']
+			return 55
+		case r == 117: // ['u','u']
+			return 65
+		case 118 <= r && r <= 122: // ['v','z']
+			return 55
 		}
+		return NoState
 	},
 	// S32
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 49
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 101: // ['a','e']
+			return 55
+		case r == 102: // ['f','f']
+			return 66
+		case 103 <= r && r <= 108: // ['g','l']
+			return 55
+		case r == 109: // ['m','m']
+			return 67
+		case r == 110: // ['n','n']
+			return 68
+		case 111 <= r && r <= 122: // ['o','z']
+			return 55
 		}
 		return NoState
 	},
@@ -436,7 +442,17 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 33
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 110: // ['a','n']
+			return 55
+		case r == 111: // ['o','o']
+			return 69
+		case 112 <= r && r <= 122: // ['p','z']
+			return 55
 		}
 		return NoState
 	},
@@ -444,19 +460,35 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 122: // ['a','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 113: // ['a','q']
+			return 55
+		case r == 114: // ['r','r']
+			return 70
+		case 115 <= r && r <= 122: // ['s','z']
+			return 55
 		}
 		return NoState
 	},
 	// S35
 	func(r rune) int {
 		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 100: // ['a','d']
+			return 55
+		case r == 101: // ['e','e']
+			return 71
+		case 102 <= r && r <= 122: // ['f','z']
+			return 55
 		}
 		return NoState
 	},
@@ -464,595 +496,1554 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 102: // ['a','f']
-			return 16
-		case r == 103: // ['g','g']
-			return 50
-		case 104 <= r && r <= 122: // ['h','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 115: // ['a','s']
+			return 55
+		case r == 116: // ['t','t']
+			return 72
+		case 117 <= r && r <= 118: // ['u','v']
+			return 55
+		case r == 119: // ['w','w']
+			return 73
+		case 120 <= r && r <= 122: // ['x','z']
+			return 55
+		}
+		return NoState
+	},
+	// S37
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 120: // ['a','x']
+			return 55
+		case r == 121: // ['y','y']
+			return 74
+		case r == 122: // ['z','z']
+			return 55
+		}
+		return NoState
+	},
+	// S38
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case r == 97: // ['a','a']
+			return 75
+		case 98 <= r && r <= 122: // ['b','z']
+			return 55
+		}
+		return NoState
+	},
+	// S39
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 103: // ['a','g']
+			return 55
+		case r == 104: // ['h','h']
+			return 76
+		case 105 <= r && r <= 122: // ['i','z']
+			return 55
+		}
+		return NoState
+	},
+	// S40
+	func(r rune) int {
+		switch {
+		}
+		return NoState
+	},
+	// S41
+	func(r rune) int {
+		switch {
+		}
+		return NoState
+	},
+	// S42
+	func(r rune) int {
+		switch {
+		}
+		return NoState
+	},
+	// S43
+	func(r rune) int {
+		switch {
+		case r == 34: // ['"','"']
+			return 44
+		case r == 92: // ['\','\']
+			return 45
+		default:
+			return 43
+		}
+	},
+	// S44
+	func(r rune) int {
+		switch {
+		}
+		return NoState
+	},
+	// S45
+	func(r rune) int {
+		switch {
+		case r == 34: // ['"','"']
+			return 77
+		case r == 110: // ['n','n']
+			return 78
+		case r == 114: // ['r','r']
+			return 79
+		case r == 116: // ['t','t']
+			return 80
+		}
+		return NoState
+	},
+	// S46
+	func(r rune) int {
+		switch {
+		}
+		return NoState
+	},
+	// S47
+	func(r rune) int {
+		switch {
+		case r == 42: // ['*','*']
+			return 82
+		default:
+			return 81
+		}
+	},
+	// S48
+	func(r rune) int {
+		switch {
+		case r == 10: // ['\n','\n']
+			return 84
+		default:
+			return 83
+		}
+	},
+	// S49
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 85
+		}
+		return NoState
+	},
+	// S50
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 50
+		}
+		return NoState
+	},
+	// S51
+	func(r rune) int {
+		switch {
+		}
+		return NoState
+	},
+	// S52
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
+		}
+		return NoState
+	},
+	// S53
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
+		}
+		return NoState
+	},
+	// S54
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
+		}
+		return NoState
+	},
+	// S55
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
+		}
+		return NoState
+	},
+	// S56
+	func(r rune) int {
+		switch {
+		case r == 96: // ['`','`']
+			return 57
+		default:
+			return 56
+		}
+	},
+	// S57
+	func(r rune) int {
+		switch {
+		}
+		return NoState
+	},
+	// S58
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 102: // ['a','f']
+			return 55
+		case r == 103: // ['g','g']
+			return 86
+		case 104 <= r && r <= 122: // ['h','z']
+			return 55
+		}
+		return NoState
+	},
+	// S59
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 114: // ['a','r']
+			return 55
+		case r == 115: // ['s','s']
+			return 87
+		case 116 <= r && r <= 122: // ['t','z']
+			return 55
+		}
+		return NoState
+	},
+	// S60
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 109: // ['a','m']
+			return 55
+		case r == 110: // ['n','n']
+			return 88
+		case 111 <= r && r <= 122: // ['o','z']
+			return 55
+		}
+		return NoState
+	},
+	// S61
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 101: // ['a','e']
+			return 55
+		case r == 102: // ['f','f']
+			return 89
+		case 103 <= r && r <= 122: // ['g','z']
+			return 55
+		}
+		return NoState
+	},
+	// S62
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 114: // ['a','r']
+			return 55
+		case r == 115: // ['s','s']
+			return 90
+		case 116 <= r && r <= 122: // ['t','z']
+			return 55
+		}
+		return NoState
+	},
+	// S63
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 99: // ['a','c']
+			return 55
+		case r == 100: // ['d','d']
+			return 91
+		case 101 <= r && r <= 122: // ['e','z']
+			return 55
+		}
+		return NoState
+	},
+	// S64
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 110: // ['a','n']
+			return 55
+		case r == 111: // ['o','o']
+			return 92
+		case 112 <= r && r <= 122: // ['p','z']
+			return 55
+		}
+		return NoState
+	},
+	// S65
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 109: // ['a','m']
+			return 55
+		case r == 110: // ['n','n']
+			return 93
+		case 111 <= r && r <= 122: // ['o','z']
+			return 55
+		}
+		return NoState
+	},
+	// S66
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
+		}
+		return NoState
+	},
+	// S67
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 111: // ['a','o']
+			return 55
+		case r == 112: // ['p','p']
+			return 94
+		case 113 <= r && r <= 122: // ['q','z']
+			return 55
+		}
+		return NoState
+	},
+	// S68
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 115: // ['a','s']
+			return 55
+		case r == 116: // ['t','t']
+			return 95
+		case 117 <= r && r <= 122: // ['u','z']
+			return 55
+		}
+		return NoState
+	},
+	// S69
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 99: // ['a','c']
+			return 55
+		case r == 100: // ['d','d']
+			return 96
+		case 101 <= r && r <= 122: // ['e','z']
+			return 55
+		}
+		return NoState
+	},
+	// S70
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 104: // ['a','h']
+			return 55
+		case r == 105: // ['i','i']
+			return 97
+		case 106 <= r && r <= 110: // ['j','n']
+			return 55
+		case r == 111: // ['o','o']
+			return 98
+		case 112 <= r && r <= 122: // ['p','z']
+			return 55
+		}
+		return NoState
+	},
+	// S71
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 101: // ['a','e']
+			return 55
+		case r == 102: // ['f','f']
+			return 99
+		case 103 <= r && r <= 122: // ['g','z']
+			return 55
+		}
+		return NoState
+	},
+	// S72
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 113: // ['a','q']
+			return 55
+		case r == 114: // ['r','r']
+			return 100
+		case 115 <= r && r <= 122: // ['s','z']
+			return 55
+		}
+		return NoState
+	},
+	// S73
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 104: // ['a','h']
+			return 55
+		case r == 105: // ['i','i']
+			return 101
+		case 106 <= r && r <= 122: // ['j','z']
+			return 55
+		}
+		return NoState
+	},
+	// S74
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 111: // ['a','o']
+			return 55
+		case r == 112: // ['p','p']
+			return 102
+		case 113 <= r && r <= 122: // ['q','z']
+			return 55
+		}
+		return NoState
+	},
+	// S75
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 113: // ['a','q']
+			return 55
+		case r == 114: // ['r','r']
+			return 103
+		case 115 <= r && r <= 122: // ['s','z']
+			return 55
+		}
+		return NoState
+	},
+	// S76
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 104: // ['a','h']
+			return 55
+		case r == 105: // ['i','i']
+			return 104
+		case 106 <= r && r <= 122: // ['j','z']
+			return 55
+		}
+		return NoState
+	},
+	// S77
+	func(r rune) int {
+		switch {
+		case r == 34: // ['"','"']
+			return 44
+		case r == 92: // ['\','\']
+			return 45
+		default:
+			return 43
+		}
+	},
+	// S78
+	func(r rune) int {
+		switch {
+		case r == 34: // ['"','"']
+			return 44
+		case r == 92: // ['\','\']
+			return 45
+		default:
+			return 43
+		}
+	},
+	// S79
+	func(r rune) int {
+		switch {
+		case r == 34: // ['"','"']
+			return 44
+		case r == 92: // ['\','\']
+			return 45
+		default:
+			return 43
+		}
+	},
+	// S80
+	func(r rune) int {
+		switch {
+		case r == 34: // ['"','"']
+			return 44
+		case r == 92: // ['\','\']
+			return 45
+		default:
+			return 43
+		}
+	},
+	// S81
+	func(r rune) int {
+		switch {
+		case r == 42: // ['*','*']
+			return 82
+		default:
+			return 81
+		}
+	},
+	// S82
+	func(r rune) int {
+		switch {
+		case r == 42: // ['*','*']
+			return 82
+		case r == 47: // ['/','/']
+			return 105
+		default:
+			return 81
+		}
+	},
+	// S83
+	func(r rune) int {
+		switch {
+		case r == 10: // ['\n','\n']
+			return 84
+		default:
+			return 83
+		}
+	},
+	// S84
+	func(r rune) int {
+		switch {
+		}
+		return NoState
+	},
+	// S85
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 106
+		}
+		return NoState
+	},
+	// S86
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 104: // ['a','h']
+			return 55
+		case r == 105: // ['i','i']
+			return 107
+		case 106 <= r && r <= 122: // ['j','z']
+			return 55
+		}
+		return NoState
+	},
+	// S87
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 100: // ['a','d']
+			return 55
+		case r == 101: // ['e','e']
+			return 108
+		case 102 <= r && r <= 122: // ['f','z']
+			return 55
+		}
+		return NoState
+	},
+	// S88
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 114: // ['a','r']
+			return 55
+		case r == 115: // ['s','s']
+			return 109
+		case 116 <= r && r <= 122: // ['t','z']
+			return 55
+		}
+		return NoState
+	},
+	// S89
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case r == 97: // ['a','a']
+			return 110
+		case 98 <= r && r <= 122: // ['b','z']
+			return 55
+		}
+		return NoState
+	},
+	// S90
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 100: // ['a','d']
+			return 55
+		case r == 101: // ['e','e']
+			return 111
+		case 102 <= r && r <= 122: // ['f','z']
+			return 55
+		}
+		return NoState
+	},
+	// S91
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
+		}
+		return NoState
+	},
+	// S92
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case r == 97: // ['a','a']
+			return 112
+		case 98 <= r && r <= 122: // ['b','z']
+			return 55
+		}
+		return NoState
+	},
+	// S93
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 98: // ['a','b']
+			return 55
+		case r == 99: // ['c','c']
+			return 113
+		case 100 <= r && r <= 122: // ['d','z']
+			return 55
+		}
+		return NoState
+	},
+	// S94
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 110: // ['a','n']
+			return 55
+		case r == 111: // ['o','o']
+			return 114
+		case 112 <= r && r <= 122: // ['p','z']
+			return 55
+		}
+		return NoState
+	},
+	// S95
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
+		}
+		return NoState
+	},
+	// S96
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 116: // ['a','t']
+			return 55
+		case r == 117: // ['u','u']
+			return 115
+		case 118 <= r && r <= 122: // ['v','z']
+			return 55
+		}
+		return NoState
+	},
+	// S97
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 109: // ['a','m']
+			return 55
+		case r == 110: // ['n','n']
+			return 116
+		case 111 <= r && r <= 122: // ['o','z']
+			return 55
+		}
+		return NoState
+	},
+	// S98
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 102: // ['a','f']
+			return 55
+		case r == 103: // ['g','g']
+			return 117
+		case 104 <= r && r <= 122: // ['h','z']
+			return 55
+		}
+		return NoState
+	},
+	// S99
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
+		}
+		return NoState
+	},
+	// S100
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 116: // ['a','t']
+			return 55
+		case r == 117: // ['u','u']
+			return 118
+		case 118 <= r && r <= 122: // ['v','z']
+			return 55
+		}
+		return NoState
+	},
+	// S101
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 115: // ['a','s']
+			return 55
+		case r == 116: // ['t','t']
+			return 119
+		case 117 <= r && r <= 122: // ['u','z']
+			return 55
+		}
+		return NoState
+	},
+	// S102
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 100: // ['a','d']
+			return 55
+		case r == 101: // ['e','e']
+			return 120
+		case 102 <= r && r <= 122: // ['f','z']
+			return 55
+		}
+		return NoState
+	},
+	// S103
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
+		}
+		return NoState
+	},
+	// S104
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 107: // ['a','k']
+			return 55
+		case r == 108: // ['l','l']
+			return 121
+		case 109 <= r && r <= 122: // ['m','z']
+			return 55
+		}
+		return NoState
+	},
+	// S105
+	func(r rune) int {
+		switch {
+		}
+		return NoState
+	},
+	// S106
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 106
 		}
 		return NoState
 	},
-	// S37
+	// S107
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 114: // ['a','r']
-			return 16
-		case r == 115: // ['s','s']
-			return 51
-		case 116 <= r && r <= 122: // ['t','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 109: // ['a','m']
+			return 55
+		case r == 110: // ['n','n']
+			return 122
+		case 111 <= r && r <= 122: // ['o','z']
+			return 55
 		}
 		return NoState
 	},
-	// S38
+	// S108
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 99: // ['a','c']
-			return 16
-		case r == 100: // ['d','d']
-			return 52
-		case 101 <= r && r <= 122: // ['e','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
 		}
 		return NoState
 	},
-	// S39
+	// S109
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
-		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 110: // ['a','n']
-			return 16
-		case r == 111: // ['o','o']
 			return 53
-		case 112 <= r && r <= 122: // ['p','z']
-			return 16
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 115: // ['a','s']
+			return 55
+		case r == 116: // ['t','t']
+			return 123
+		case 117 <= r && r <= 122: // ['u','z']
+			return 55
 		}
 		return NoState
 	},
-	// S40
+	// S110
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 109: // ['a','m']
-			return 16
-		case r == 110: // ['n','n']
 			return 54
-		case 111 <= r && r <= 122: // ['o','z']
-			return 16
+		case 97 <= r && r <= 116: // ['a','t']
+			return 55
+		case r == 117: // ['u','u']
+			return 124
+		case 118 <= r && r <= 122: // ['v','z']
+			return 55
 		}
 		return NoState
 	},
-	// S41
+	// S111
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 16
+			return 55
 		}
 		return NoState
 	},
-	// S42
+	// S112
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
+			return 54
 		case 97 <= r && r <= 115: // ['a','s']
-			return 16
-		case r == 116: // ['t','t']
 			return 55
+		case r == 116: // ['t','t']
+			return 125
 		case 117 <= r && r <= 122: // ['u','z']
-			return 16
+			return 55
 		}
 		return NoState
 	},
-	// S43
+	// S113
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 104: // ['a','h']
-			return 16
-		case r == 105: // ['i','i']
-			return 56
-		case 106 <= r && r <= 110: // ['j','n']
-			return 16
-		case r == 111: // ['o','o']
-			return 57
-		case 112 <= r && r <= 122: // ['p','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
 		}
 		return NoState
 	},
-	// S44
+	// S114
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
+			return 54
 		case 97 <= r && r <= 113: // ['a','q']
-			return 16
+			return 55
 		case r == 114: // ['r','r']
-			return 58
+			return 126
 		case 115 <= r && r <= 122: // ['s','z']
-			return 16
+			return 55
 		}
 		return NoState
 	},
-	// S45
+	// S115
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 104: // ['a','h']
-			return 16
-		case r == 105: // ['i','i']
-			return 59
-		case 106 <= r && r <= 122: // ['j','z']
-			return 16
-		}
-		return NoState
-	},
-	// S46
-	func(r rune) int {
-		switch {
-		case r == 34: // ['"','"']
-			return 28
-		case r == 92: // ['\','\']
-			return 29
-		default:
-			return 3
-		}
-	},
-	// S47
-	func(r rune) int {
-		switch {
-		case r == 42: // ['*','*']
-			return 47
-		case r == 47: // ['/','/']
-			return 60
-		default:
-			return 30
-		}
-	},
-	// S48
-	func(r rune) int {
-		switch {
+			return 54
+		case 97 <= r && r <= 107: // ['a','k']
+			return 55
+		case r == 108: // ['l','l']
+			return 127
+		case 109 <= r && r <= 122: // ['m','z']
+			return 55
 		}
 		return NoState
 	},
-	// S49
+	// S116
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 49
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 115: // ['a','s']
+			return 55
+		case r == 116: // ['t','t']
+			return 128
+		case 117 <= r && r <= 122: // ['u','z']
+			return 55
 		}
 		return NoState
 	},
-	// S50
+	// S117
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 104: // ['a','h']
-			return 16
-		case r == 105: // ['i','i']
-			return 61
-		case 106 <= r && r <= 122: // ['j','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 113: // ['a','q']
+			return 55
+		case r == 114: // ['r','r']
+			return 129
+		case 115 <= r && r <= 122: // ['s','z']
+			return 55
 		}
 		return NoState
 	},
-	// S51
+	// S118
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 100: // ['a','d']
-			return 16
-		case r == 101: // ['e','e']
-			return 62
-		case 102 <= r && r <= 122: // ['f','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 98: // ['a','b']
+			return 55
+		case r == 99: // ['c','c']
+			return 130
+		case 100 <= r && r <= 122: // ['d','z']
+			return 55
 		}
 		return NoState
 	},
-	// S52
+	// S119
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 122: // ['a','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 98: // ['a','b']
+			return 55
+		case r == 99: // ['c','c']
+			return 131
+		case 100 <= r && r <= 122: // ['d','z']
+			return 55
 		}
 		return NoState
 	},
-	// S53
+	// S120
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case r == 97: // ['a','a']
-			return 63
-		case 98 <= r && r <= 122: // ['b','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
 		}
 		return NoState
 	},
-	// S54
+	// S121
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 98: // ['a','b']
-			return 16
-		case r == 99: // ['c','c']
-			return 64
-		case 100 <= r && r <= 122: // ['d','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 100: // ['a','d']
+			return 55
+		case r == 101: // ['e','e']
+			return 132
+		case 102 <= r && r <= 122: // ['f','z']
+			return 55
 		}
 		return NoState
 	},
-	// S55
+	// S122
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 16
+			return 55
 		}
 		return NoState
 	},
-	// S56
+	// S123
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 109: // ['a','m']
-			return 16
-		case r == 110: // ['n','n']
-			return 65
-		case 111 <= r && r <= 122: // ['o','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
 		}
 		return NoState
 	},
-	// S57
+	// S124
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 102: // ['a','f']
-			return 16
-		case r == 103: // ['g','g']
-			return 66
-		case 104 <= r && r <= 122: // ['h','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 107: // ['a','k']
+			return 55
+		case r == 108: // ['l','l']
+			return 133
+		case 109 <= r && r <= 122: // ['m','z']
+			return 55
 		}
 		return NoState
 	},
-	// S58
+	// S125
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 16
+			return 55
 		}
 		return NoState
 	},
-	// S59
+	// S126
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 107: // ['a','k']
-			return 16
-		case r == 108: // ['l','l']
-			return 67
-		case 109 <= r && r <= 122: // ['m','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 115: // ['a','s']
+			return 55
+		case r == 116: // ['t','t']
+			return 134
+		case 117 <= r && r <= 122: // ['u','z']
+			return 55
 		}
 		return NoState
 	},
-	// S60
+	// S127
 	func(r rune) int {
 		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 52
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 53
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 100: // ['a','d']
+			return 55
+		case r == 101: // ['e','e']
+			return 135
+		case 102 <= r && r <= 122: // ['f','z']
+			return 55
 		}
 		return NoState
 	},
-	// S61
+	// S128
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 109: // ['a','m']
-			return 16
-		case r == 110: // ['n','n']
-			return 68
-		case 111 <= r && r <= 122: // ['o','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
 		}
 		return NoState
 	},
-	// S62
+	// S129
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 122: // ['a','z']
-			return 16
+			return 54
+		case r == 97: // ['a','a']
+			return 136
+		case 98 <= r && r <= 122: // ['b','z']
+			return 55
 		}
 		return NoState
 	},
-	// S63
+	// S130
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
+			return 54
 		case 97 <= r && r <= 115: // ['a','s']
-			return 16
-		case r == 116: // ['t','t']
 			return 55
+		case r == 116: // ['t','t']
+			return 137
 		case 117 <= r && r <= 122: // ['u','z']
-			return 16
+			return 55
 		}
 		return NoState
 	},
-	// S64
+	// S131
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 122: // ['a','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 103: // ['a','g']
+			return 55
+		case r == 104: // ['h','h']
+			return 138
+		case 105 <= r && r <= 122: // ['i','z']
+			return 55
 		}
 		return NoState
 	},
-	// S65
+	// S132
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 115: // ['a','s']
-			return 16
-		case r == 116: // ['t','t']
-			return 69
-		case 117 <= r && r <= 122: // ['u','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
 		}
 		return NoState
 	},
-	// S66
+	// S133
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 113: // ['a','q']
-			return 16
-		case r == 114: // ['r','r']
-			return 70
-		case 115 <= r && r <= 122: // ['s','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 115: // ['a','s']
+			return 55
+		case r == 116: // ['t','t']
+			return 139
+		case 117 <= r && r <= 122: // ['u','z']
+			return 55
 		}
 		return NoState
 	},
-	// S67
+	// S134
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 100: // ['a','d']
-			return 16
-		case r == 101: // ['e','e']
-			return 71
-		case 102 <= r && r <= 122: // ['f','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
 		}
 		return NoState
 	},
-	// S68
+	// S135
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 16
+			return 55
 		}
 		return NoState
 	},
-	// S69
+	// S136
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 122: // ['a','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 108: // ['a','l']
+			return 55
+		case r == 109: // ['m','m']
+			return 140
+		case 110 <= r && r <= 122: // ['n','z']
+			return 55
 		}
 		return NoState
 	},
-	// S70
+	// S137
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case r == 97: // ['a','a']
-			return 72
-		case 98 <= r && r <= 122: // ['b','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
 		}
 		return NoState
 	},
-	// S71
+	// S138
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 16
+			return 55
 		}
 		return NoState
 	},
-	// S72
+	// S139
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
-		case 97 <= r && r <= 108: // ['a','l']
-			return 16
-		case r == 109: // ['m','m']
-			return 73
-		case 110 <= r && r <= 122: // ['n','z']
-			return 16
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 55
 		}
 		return NoState
 	},
-	// S73
+	// S140
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 34
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 16
+			return 53
 		case r == 95: // ['_','_']
-			return 17
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 16
+			return 55
 		}
 		return NoState
 	},
@@ -0,0 +1,379 @@
+package linker
+
+import (
+	"fmt"
+	"pogo/src/semantic"
+	"pogo/src/shared"
+	"pogo/src/virtualmachine"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Unit is a compiled module ready to be linked into a program. Its quads
+// run the module initializers and then jump over its functions, so linking
+// it at the start of a program initializes the module before main. Units
+// saved to disk are object files.
+type Unit struct {
+	Name       string
+	Module     string // the name in the module header, empty for a program
+	Main       bool   // the unit is a program, its main section ends the linked program
+	Quads      []shared.Quadruple
+	Functions  []shared.Function                     // the functions the module defines
+	Externals  []shared.Function                     // functions it calls that other units define
+	Structs    []shared.StructDef                    // the struct types the module declares
+	References []Reference                           // globals of other units it uses
+	Variables  map[string]shared.Variable            // module globals and constants
+	Scopes     map[string]map[string]shared.Variable // function and block scopes
+	Memory     *virtualmachine.MemoryManager
+}
+
+// Reference is a global of an imported module used by a unit. The unit is
+// compiled with a global of its own at Address, which linking replaces with
+// the global of the module.
+type Reference struct {
+	Module  string // the path of the module
+	Name    string
+	Address int
+}
+
+// ModuleScope is the name of the scope that keeps the globals of a linked module.
+func ModuleScope(module string) string {
+	return "module:" + module
+}
+
+// relocator maps the addresses of a unit to addresses of the program it is
+// linked into. Locals are relative to the function frame and keep their
+// address, globals and temporaries get new slots and constants are moved to
+// the constant table of the program.
+type relocator struct {
+	unit      *Unit
+	target    *semantic.QuadrupleList
+	offset    int
+	addresses map[int]int
+}
+
+// Link appends unit to the quads of target, moving every quad index by the
+// current length of target and every address as described by relocator.
+// The functions of the unit are declared in st and calls are resolved by
+// name, so they may also target functions of previously linked units.
+func Link(target *semantic.QuadrupleList, st *semantic.SymbolTable, unit *Unit) ([]shared.Function, error) {
+	start := len(target.Quads)
+
+	functions, err := link(target, st, unit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resolveCalls(target.Quads[start:], st, unit.Name); err != nil {
+		return nil, err
+	}
+	return functions, nil
+}
+
+// LinkObjects links object files into one program. The units without a main
+// section are laid out first, every unit after the units it imports and
+// otherwise in the given order, so their initializers run before the main
+// section of the only program unit, which goes last. Calls to external
+// functions are resolved once every unit is in place.
+func LinkObjects(units []*Unit) (*semantic.QuadrupleList, *semantic.SymbolTable, error) {
+	var program *Unit
+	for _, unit := range units {
+		if !unit.Main {
+			continue
+		}
+		if program != nil {
+			return nil, nil, fmt.Errorf("both '%s' and '%s' have a main section", program.Name, unit.Name)
+		}
+		program = unit
+	}
+	if program == nil {
+		return nil, nil, fmt.Errorf("no object has a main section")
+	}
+	ordered := order(units, program)
+
+	target := semantic.NewQuadrupleList()
+	st := semantic.NewSymbolTable()
+
+	starts := make([]int, len(ordered))
+	for i, unit := range ordered {
+		starts[i] = len(target.Quads)
+		if _, err := link(target, st, unit); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for i, unit := range ordered {
+		if err := checkExternals(st, unit); err != nil {
+			return nil, nil, err
+		}
+
+		end := len(target.Quads)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		if err := resolveCalls(target.Quads[starts[i]:end], st, unit.Name); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return target, st, nil
+}
+
+// order sorts units so that every unit comes after the modules it depends
+// on, with program last.
+func order(units []*Unit, program *Unit) []*Unit {
+	byName := make(map[string]*Unit, len(units))
+	for _, unit := range units {
+		byName[unit.Name] = unit
+	}
+
+	ordered := make([]*Unit, 0, len(units))
+	visited := make(map[*Unit]bool, len(units))
+	var visit func(unit *Unit)
+	visit = func(unit *Unit) {
+		if unit == nil || visited[unit] {
+			return
+		}
+		visited[unit] = true
+		for _, external := range unit.Externals {
+			visit(byName[external.Module])
+		}
+		for _, reference := range unit.References {
+			visit(byName[reference.Module])
+		}
+		ordered = append(ordered, unit)
+	}
+
+	for _, unit := range units {
+		if unit != program {
+			visit(unit)
+		}
+	}
+	visit(program)
+	return ordered
+}
+
+// checkExternals makes sure the functions a unit was compiled against exist
+// and still take the same parameters.
+func checkExternals(st *semantic.SymbolTable, unit *Unit) error {
+	for _, external := range unit.Externals {
+		function, ok := st.GetGlobalScope()[external.Name].(shared.Function)
+		if !ok {
+			return fmt.Errorf("%s: unresolved external function '%s'", unit.Name, external.Name)
+		}
+
+		same := len(function.Parameters) == len(external.Parameters)
+		for i := 0; same && i < len(function.Parameters); i++ {
+			same = function.Parameters[i].Type == external.Parameters[i].Type &&
+				function.Parameters[i].Struct == external.Parameters[i].Struct &&
+				function.Parameters[i].Ref == external.Parameters[i].Ref
+		}
+		if !same {
+			return fmt.Errorf("%s: function '%s' does not match the signature it was compiled against (%s)",
+				unit.Name, external.Name, function.Module)
+		}
+	}
+	return nil
+}
+
+// resolveCalls points every gosub to the start of the function it calls.
+func resolveCalls(quads []shared.Quadruple, st *semantic.SymbolTable, unit string) error {
+	for i, quad := range quads {
+		if quad.Operator != "gosub" {
+			continue
+		}
+		start, err := st.GetFunctionStartQuad(quad.LeftOp.(string))
+		if err != nil {
+			return fmt.Errorf("%s: unresolved external function '%s'", unit, quad.LeftOp)
+		}
+		quads[i].Result = start
+	}
+	return nil
+}
+
+func link(target *semantic.QuadrupleList, st *semantic.SymbolTable, unit *Unit) ([]shared.Function, error) {
+	r := &relocator{
+		unit:      unit,
+		target:    target,
+		offset:    len(target.Quads),
+		addresses: make(map[int]int),
+	}
+
+	for _, reference := range unit.References {
+		name, path, _ := strings.Cut(reference.Name, ".")
+		variable, ok := st.GetScopes()[ModuleScope(reference.Module)][name].(shared.Variable)
+		if ok && path != "" {
+			variable, ok = variable.Field(path)
+		}
+		if !ok || variable.Type == shared.TypeStruct {
+			return nil, fmt.Errorf("%s: unresolved external variable '%s' of '%s'", unit.Name, reference.Name, reference.Module)
+		}
+		r.addresses[reference.Address] = variable.Address
+	}
+
+	for _, def := range unit.Structs {
+		def.Module = unit.Name
+		if err := st.AddImportedStruct(def); err != nil {
+			return nil, err
+		}
+	}
+
+	functions := make([]shared.Function, 0, len(unit.Functions))
+	for _, function := range unit.Functions {
+		function.StartQuad += r.offset
+		function.Module = unit.Name
+		if err := st.AddImportedFunction(function); err != nil {
+			return nil, err
+		}
+		functions = append(functions, function)
+	}
+
+	for _, quad := range unit.Quads {
+		relocated, err := r.quad(quad)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", unit.Name, err)
+		}
+
+		target.Quads = append(target.Quads, relocated)
+	}
+
+	variables := make(map[string]shared.Variable, len(unit.Variables))
+	for name, variable := range unit.Variables {
+		relocated, err := r.variable(variable)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", unit.Name, err)
+		}
+		variables[name] = relocated
+	}
+	st.AddScope(ModuleScope(unit.Name), variables)
+
+	for name, scope := range unit.Scopes {
+		st.AddScope(name, scope)
+	}
+
+	sort.Slice(functions, func(i, j int) bool { return functions[i].Name < functions[j].Name })
+	return functions, nil
+}
+
+func (r *relocator) quad(quad shared.Quadruple) (shared.Quadruple, error) {
+	info, exists := shared.Operators[quad.Operator]
+	if !exists {
+		return quad, fmt.Errorf("cannot relocate unknown operator '%s'", quad.Operator)
+	}
+
+	var err error
+	if quad.LeftOp, err = r.operand(info.Left, quad.LeftOp); err != nil {
+		return quad, err
+	}
+	if quad.RightOp, err = r.operand(info.Right, quad.RightOp); err != nil {
+		return quad, err
+	}
+	if quad.Result, err = r.operand(info.Result, quad.Result); err != nil {
+		return quad, err
+	}
+	return quad, nil
+}
+
+func (r *relocator) operand(kind shared.OperandKind, value interface{}) (interface{}, error) {
+	switch kind {
+	case shared.OperandAddress, shared.OperandReference:
+		if addr, ok := value.(int); ok {
+			return r.address(addr)
+		}
+	case shared.OperandAddressList:
+		addresses := make([]int, len(value.([]int)))
+		for i, addr := range value.([]int) {
+			relocated, err := r.address(addr)
+			if err != nil {
+				return nil, err
+			}
+			addresses[i] = relocated
+		}
+		return addresses, nil
+	case shared.OperandQuad:
+		return value.(int) + r.offset, nil
+	case shared.OperandQuadList:
+		targets := make([]int, len(value.([]int)))
+		for i, target := range value.([]int) {
+			targets[i] = target + r.offset
+		}
+		return targets, nil
+	}
+	return value, nil
+}
+
+// variable relocates the address of a variable, or of every field of a struct.
+func (r *relocator) variable(variable shared.Variable) (shared.Variable, error) {
+	if variable.Type != shared.TypeStruct {
+		addr, err := r.address(variable.Address)
+		variable.Address = addr
+		return variable, err
+	}
+
+	fields := make([]shared.Variable, len(variable.Fields))
+	for i, field := range variable.Fields {
+		relocated, err := r.variable(field)
+		if err != nil {
+			return variable, err
+		}
+		fields[i] = relocated
+	}
+	variable.Fields = fields
+	return variable, nil
+}
+
+func (r *relocator) address(addr int) (int, error) {
+	if relocated, exists := r.addresses[addr]; exists {
+		return relocated, nil
+	}
+
+	var relocated int
+	var err error
+	switch {
+	case addr >= virtualmachine.LOCAL_START && addr < virtualmachine.TEMP_START:
+		return addr, nil
+	case addr >= virtualmachine.GLOBAL_START && addr < virtualmachine.LOCAL_START:
+		relocated, err = r.target.MemoryManager.AllocateGlobal(segmentType(addr, virtualmachine.GLOBAL_FLOAT_START))
+	case addr >= virtualmachine.TEMP_START && addr < virtualmachine.CONSTANT_START:
+		relocated, err = r.target.NewTemp(segmentType(addr, virtualmachine.TEMP_FLOAT_START))
+	default:
+		relocated, err = r.constant(addr)
+	}
+	if err != nil {
+		return -1, err
+	}
+
+	r.addresses[addr] = relocated
+	return relocated, nil
+}
+
+func (r *relocator) constant(addr int) (int, error) {
+	return CopyConstant(r.unit.Memory, r.target.MemoryManager, addr)
+}
+
+// CopyConstant adds the constant at addr in from to the constant table of
+// to and returns its address there.
+func CopyConstant(from, to *virtualmachine.MemoryManager, addr int) (int, error) {
+	value, exists := from.GetConstant(addr)
+	if !exists {
+		return -1, fmt.Errorf("unknown constant address %d", addr)
+	}
+
+	switch v := value.(type) {
+	case int:
+		return to.AllocateConstant(strconv.Itoa(v))
+	case float64:
+		return to.AllocateConstant(semantic.FloatConstant(v).Literal())
+	case string:
+		return to.AllocateStringAddress(v)
+	}
+	return -1, fmt.Errorf("unsupported constant %v at address %d", value, addr)
+}
+
+func segmentType(addr, floatStart int) shared.Type {
+	if addr >= floatStart {
+		return shared.TypeFloat
+	}
+	return shared.TypeInt
+}
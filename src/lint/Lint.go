@@ -0,0 +1,199 @@
+// Package lint finds code that compiles but is likely a mistake: variables
+// and parameters that are never read, code behind conditions that are always
+// false, locals that shadow globals and functions that are never called.
+// Every rule can be turned off on its own.
+package lint
+
+import (
+	"fmt"
+	"pogo/src/analysis"
+	"pogo/src/ast"
+	"pogo/src/lexer"
+	"pogo/src/parser"
+	"pogo/src/semantic"
+	"pogo/src/shared"
+	"sort"
+)
+
+// Rule is a check of the linter, its name is the one used to turn it on or
+// off.
+type Rule struct {
+	Name        string
+	Description string
+	check       func(l *linter)
+}
+
+// Rules lists every rule, all of them run unless a Config says otherwise.
+var Rules = []Rule{
+	{"unused-variable", "variables that are never read, assigned or not", checkUnusedVariables},
+	{"unused-parameter", "parameters that are never read", checkUnusedParameters},
+	{"unreachable-code", "blocks behind conditions that are always false", checkUnreachableCode},
+	{"shadowed-global", "parameters and local variables named like a global", checkShadowedGlobals},
+	{"unused-function", "functions that are never called from the program", checkUnusedFunctions},
+}
+
+// Config picks the rules that run. When Only is set just those rules run,
+// rules in Disable never run.
+type Config struct {
+	Only    []string
+	Disable []string
+}
+
+// enabled returns the rules config turns on, or an error naming a rule
+// that does not exist.
+func (c Config) enabled() ([]Rule, error) {
+	known := make(map[string]bool)
+	for _, rule := range Rules {
+		known[rule.Name] = true
+	}
+
+	selected := make(map[string]bool)
+	for _, name := range c.Only {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown lint rule '%s'", name)
+		}
+		selected[name] = true
+	}
+	disabled := make(map[string]bool)
+	for _, name := range c.Disable {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown lint rule '%s'", name)
+		}
+		disabled[name] = true
+	}
+
+	rules := make([]Rule, 0, len(Rules))
+	for _, rule := range Rules {
+		if disabled[rule.Name] || (len(c.Only) > 0 && !selected[rule.Name]) {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Source compiles a program or a module and lints it. Imported modules are
+// only read for what they declare, findings are about filename alone.
+func Source(src []byte, filename string, config Config) ([]shared.Diagnostic, error) {
+	rules, err := config.enabled()
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser.NewParser(lexer.NewLexer(src))
+	p.Filename = filename
+	p.SeparateImports = true
+
+	if _, err := p.Parse(); err != nil {
+		return nil, err
+	}
+	return run(p, rules), nil
+}
+
+// Check lints a file p has compiled without errors.
+func Check(p *parser.Parser, config Config) ([]shared.Diagnostic, error) {
+	rules, err := config.enabled()
+	if err != nil {
+		return nil, err
+	}
+	return run(p, rules), nil
+}
+
+type linter struct {
+	tree        *ast.Program
+	symbols     *semantic.SymbolTable
+	quads       []shared.Quadruple
+	graph       *analysis.ControlFlowGraph
+	functions   map[string]shared.Function // the functions with code in this file
+	diagnostics []shared.Diagnostic
+	rule        string
+}
+
+func run(p *parser.Parser, rules []Rule) []shared.Diagnostic {
+	l := &linter{
+		tree:        p.Tree(),
+		symbols:     p.SymbolTable,
+		quads:       p.CodeGenerator.Quads,
+		functions:   make(map[string]shared.Function),
+		diagnostics: make([]shared.Diagnostic, 0),
+	}
+
+	starts := make(map[string]int)
+	for name, symbol := range l.symbols.GetGlobalScope() {
+		if function, ok := symbol.(shared.Function); ok && function.Module == "" && function.StartQuad >= 0 {
+			l.functions[name] = function
+			starts[name] = function.StartQuad
+		}
+	}
+	l.graph = analysis.BuildControlFlowGraph(l.quads, starts)
+
+	for _, rule := range rules {
+		l.rule = rule.Name
+		rule.check(l)
+	}
+
+	sort.SliceStable(l.diagnostics, func(i, j int) bool {
+		if l.diagnostics[i].Line != l.diagnostics[j].Line {
+			return l.diagnostics[i].Line < l.diagnostics[j].Line
+		}
+		if l.diagnostics[i].Column != l.diagnostics[j].Column {
+			return l.diagnostics[i].Column < l.diagnostics[j].Column
+		}
+		return l.diagnostics[i].Rule < l.diagnostics[j].Rule
+	})
+	return l.diagnostics
+}
+
+func (l *linter) report(line, column int, format string, args ...interface{}) {
+	l.diagnostics = append(l.diagnostics, shared.Diagnostic{
+		Severity: shared.SeverityWarning,
+		Line:     line,
+		Column:   column,
+		Message:  fmt.Sprintf(format, args...),
+		Rule:     l.rule,
+	})
+}
+
+// owner returns the code a scope belongs to as the control flow graph names
+// it, false for the scopes of imported modules.
+func (l *linter) owner(scope string) (string, bool) {
+	switch owner := semantic.ScopeOwner(scope); owner {
+	case "global", semantic.MainScope:
+		return analysis.ProgramBody, true
+	default:
+		_, ok := l.functions[owner]
+		return owner, ok
+	}
+}
+
+// uses returns the addresses read or passed by reference by the code of
+// every function, and written when writes is set.
+func (l *linter) uses(writes bool) map[string]map[int]bool {
+	uses := make(map[string]map[int]bool)
+	for i, quad := range l.quads {
+		function := l.graph.BlockOf(i).Function
+		if uses[function] == nil {
+			uses[function] = make(map[int]bool)
+		}
+		for _, addr := range append(quad.ReadAddresses(), quad.ReferencedAddresses()...) {
+			uses[function][addr] = true
+		}
+		if addr, ok := quad.WrittenAddress(); ok && writes {
+			uses[function][addr] = true
+		}
+	}
+	return uses
+}
+
+func (l *linter) isParameter(scope, name string) bool {
+	function, ok := l.functions[scope]
+	if !ok {
+		return false
+	}
+	for _, param := range function.Parameters {
+		if param.Name == name {
+			return true
+		}
+	}
+	return false
+}
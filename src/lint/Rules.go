@@ -0,0 +1,254 @@
+package lint
+
+import (
+	"pogo/src/analysis"
+	"pogo/src/ast"
+	"pogo/src/semantic"
+	"pogo/src/shared"
+	"strconv"
+)
+
+// checkUnusedVariables reports variables none of whose slots is read by the
+// code they belong to. Globals may be read by any function, the globals of a
+// module are read by the programs importing it.
+func checkUnusedVariables(l *linter) {
+	reads := l.uses(false)
+	anywhere := make(map[int]bool)
+	for _, addresses := range reads {
+		for addr := range addresses {
+			anywhere[addr] = true
+		}
+	}
+
+	for scope, symbols := range l.symbols.GetScopes() {
+		owner, ok := l.owner(scope)
+		if !ok || (scope == "global" && l.tree.Module) {
+			continue
+		}
+
+		for name, symbol := range symbols {
+			variable, ok := symbol.(shared.Variable)
+			if !ok || variable.Constant || l.isParameter(scope, name) {
+				continue
+			}
+
+			used := reads[owner]
+			if scope == "global" {
+				used = anywhere
+			}
+			if !anyAddress(variable, used) {
+				l.report(variable.Line, variable.Column, "variable '%s' is never read", name)
+			}
+		}
+	}
+}
+
+// checkUnusedParameters reports parameters their function never reads. A
+// parameter passed by reference is also used when it is assigned, that is
+// how a function returns values.
+func checkUnusedParameters(l *linter) {
+	reads := l.uses(false)
+	accesses := l.uses(true)
+
+	for name, function := range l.functions {
+		for _, param := range function.Parameters {
+			used := reads[name]
+			if param.Ref {
+				used = accesses[name]
+			}
+			if !anyAddress(param, used) {
+				l.report(param.Line, param.Column, "parameter '%s' is never used", param.Name)
+			}
+		}
+	}
+}
+
+func anyAddress(variable shared.Variable, addresses map[int]bool) bool {
+	for _, scalar := range shared.Scalars([]shared.Variable{variable}) {
+		if addresses[scalar.Address] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkUnreachableCode reports the blocks of ifs and whiles whose condition
+// is made of literals and never lets them run, the else of an if that is
+// always true included. Only the first statement of such a block is
+// reported.
+func checkUnreachableCode(l *linter) {
+	unreachable := func(block ast.Node, condition string) {
+		var stmts []ast.Stmt
+		switch b := block.(type) {
+		case *ast.Block:
+			stmts = b.Stmts
+		case *ast.IfStmt:
+			stmts = []ast.Stmt{b}
+		}
+		if len(stmts) > 0 {
+			pos := stmts[0].Position()
+			l.report(pos.Line, pos.Column, "unreachable code, the condition is always %s", condition)
+		}
+	}
+
+	ast.Inspect(l.tree, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.IfStmt:
+			value, ok := constantCondition(n.Cond)
+			if !ok {
+				return true
+			}
+			if !value {
+				unreachable(n.Then, "false")
+			} else if n.Else != nil {
+				unreachable(n.Else, "true")
+			}
+		case *ast.WhileStmt:
+			if value, ok := constantCondition(n.Cond); ok && !value {
+				unreachable(n.Body, "false")
+			}
+		}
+		return true
+	})
+}
+
+// constantCondition evaluates a condition made only of literals, false is
+// returned as the second result when it depends on anything else.
+func constantCondition(expr ast.Expr) (bool, bool) {
+	value, ok := constantValue(expr)
+	if !ok || value.Type != shared.TypeInt {
+		return false, false
+	}
+	return value.Value.(int) != 0, true
+}
+
+var cube = semantic.NewSemanticCube()
+
+func constantValue(expr ast.Expr) (semantic.Constant, bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return constantValue(e.X)
+	case *ast.BasicLit:
+		switch e.Type {
+		case shared.TypeInt:
+			value, err := strconv.Atoi(e.Value)
+			return semantic.IntConstant(value), err == nil
+		case shared.TypeFloat:
+			value, err := strconv.ParseFloat(e.Value, 64)
+			return semantic.FloatConstant(value), err == nil
+		}
+	case *ast.UnaryExpr:
+		value, ok := constantValue(e.X)
+		if !ok || e.Op == "+" {
+			return value, ok
+		}
+		value, err := cube.Fold(semantic.IntConstant(-1), "*", value)
+		return value, err == nil
+	case *ast.BinaryExpr:
+		left, ok := constantValue(e.X)
+		if !ok {
+			return semantic.Constant{}, false
+		}
+		right, ok := constantValue(e.Y)
+		if !ok {
+			return semantic.Constant{}, false
+		}
+
+		// Comparisons give 1 or 0 like they do in the VM
+		l, r := number(left), number(right)
+		switch e.Op {
+		case "<":
+			return boolConstant(l < r), true
+		case ">":
+			return boolConstant(l > r), true
+		case "==":
+			return boolConstant(l == r), true
+		case "!=":
+			return boolConstant(l != r), true
+		}
+		value, err := cube.Fold(left, e.Op, right)
+		return value, err == nil
+	}
+	return semantic.Constant{}, false
+}
+
+func number(c semantic.Constant) float64 {
+	if v, ok := c.Value.(int); ok {
+		return float64(v)
+	}
+	return c.Value.(float64)
+}
+
+func boolConstant(b bool) semantic.Constant {
+	if b {
+		return semantic.IntConstant(1)
+	}
+	return semantic.IntConstant(0)
+}
+
+// checkShadowedGlobals reports parameters, variables and constants of
+// functions and of the main section that hide a global of the same name.
+func checkShadowedGlobals(l *linter) {
+	globals := l.symbols.GetGlobalScope()
+
+	for scope, symbols := range l.symbols.GetScopes() {
+		if _, ok := l.owner(scope); !ok || scope == "global" {
+			continue
+		}
+
+		for name, symbol := range symbols {
+			variable, ok := symbol.(shared.Variable)
+			if !ok {
+				continue
+			}
+			if _, ok := globals[name].(shared.Variable); !ok {
+				continue
+			}
+
+			kind := "variable"
+			switch {
+			case l.isParameter(scope, name):
+				kind = "parameter"
+			case variable.Constant:
+				kind = "constant"
+			}
+			l.report(variable.Line, variable.Column, "%s '%s' shadows the global '%s'", kind, name, name)
+		}
+	}
+}
+
+// checkUnusedFunctions reports the functions of a program that can't be
+// reached by calls from the main section or the global initializers. A
+// module's functions are called by the programs importing it.
+func checkUnusedFunctions(l *linter) {
+	if l.tree.Module {
+		return
+	}
+
+	calls := make(map[string][]string)
+	for i, quad := range l.quads {
+		if quad.Operator == "gosub" {
+			caller := l.graph.BlockOf(i).Function
+			calls[caller] = append(calls[caller], quad.LeftOp.(string))
+		}
+	}
+
+	called := map[string]bool{analysis.ProgramBody: true}
+	pending := []string{analysis.ProgramBody}
+	for len(pending) > 0 {
+		caller := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, callee := range calls[caller] {
+			if !called[callee] {
+				called[callee] = true
+				pending = append(pending, callee)
+			}
+		}
+	}
+
+	for name, function := range l.functions {
+		if !called[name] {
+			l.report(function.Line, function.Column, "function '%s' is never called", name)
+		}
+	}
+}
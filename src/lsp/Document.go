@@ -0,0 +1,467 @@
+package lsp
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"pogo/src/analysis"
+	"pogo/src/builtins"
+	"pogo/src/lexer"
+	"pogo/src/parser"
+	"pogo/src/semantic"
+	"pogo/src/shared"
+	"pogo/src/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// document is an open file as of its last change. The symbols and scopes
+// are what code generation got to, so a document with an error still
+// resolves the names declared before it. Nothing is declared when the file
+// has a syntax error, the server keeps the symbols of the last version that
+// parsed instead.
+type document struct {
+	uri         string
+	lines       []string
+	tokens      []*token.Token
+	symbols     *semantic.SymbolTable
+	scopes      []parser.ScopeSpan
+	parsed      bool // the file has no syntax errors
+	diagnostics []Diagnostic
+}
+
+var keywords = []string{
+	"begin", "case", "const", "default", "else", "end", "float", "func", "if", "import",
+	"int", "module", "print", "program", "ref", "struct", "switch", "type", "var", "while",
+}
+
+// errorLine matches the line compile errors start with.
+var errorLine = regexp.MustCompile(`^line (\d+): `)
+
+// analyze parses a document like an object file, so imported modules are
+// only read for the functions, globals and types they declare.
+func analyze(uri string, text []byte) *document {
+	doc := &document{
+		uri:         uri,
+		lines:       strings.Split(string(text), "\n"),
+		diagnostics: make([]Diagnostic, 0),
+	}
+
+	l := lexer.NewLexer(text)
+	for tok := l.Scan(); tok.Type != token.EOF; tok = l.Scan() {
+		doc.tokens = append(doc.tokens, tok)
+	}
+
+	p := parser.NewParser(lexer.NewLexer(text))
+	p.Filename = uriToPath(uri)
+	p.SeparateImports = true
+
+	_, err := p.Parse()
+	doc.symbols = p.SymbolTable
+	doc.scopes = p.Scopes()
+	doc.parsed = p.Tree() != nil
+	if err != nil {
+		doc.diagnostics = append(doc.diagnostics, doc.errorDiagnostic(err, p.Line()))
+		return doc
+	}
+
+	for _, diagnostic := range analysis.CheckDefiniteAssignment(p.CodeGenerator.Quads, p.SymbolTable, false) {
+		// Findings in the code of other files are theirs to report
+		if diagnostic.File != "" {
+			continue
+		}
+		doc.diagnostics = append(doc.diagnostics, Diagnostic{
+			Range:    doc.wordRange(diagnostic.Line, diagnostic.Column),
+			Severity: SeverityWarning,
+			Source:   "pogo",
+			Message:  diagnostic.Message,
+		})
+	}
+	return doc
+}
+
+// errorDiagnostic places a compile error on the line it names, or on the
+// line where parsing stopped when it names none.
+func (d *document) errorDiagnostic(err error, stopped int) Diagnostic {
+	message := err.Error()
+	line := stopped
+	if match := errorLine.FindStringSubmatch(message); match != nil {
+		line, _ = strconv.Atoi(match[1])
+		message = message[len(match[0]):]
+	}
+
+	text := d.line(line)
+	start := len(text) - len(strings.TrimLeft(text, " \t"))
+	end := len(strings.TrimRight(text, " \t\r"))
+	if end < start {
+		end = start
+	}
+	return Diagnostic{
+		Range: Range{
+			Start: Position{Line: line - 1, Character: utf16Len(text[:start])},
+			End:   Position{Line: line - 1, Character: utf16Len(text[:end])},
+		},
+		Severity: SeverityError,
+		Source:   "pogo",
+		Message:  message,
+	}
+}
+
+// line returns the text of a line counted from one.
+func (d *document) line(line int) string {
+	if line < 1 || line > len(d.lines) {
+		return ""
+	}
+	return d.lines[line-1]
+}
+
+// wordRange returns the range of the identifier starting at line and column.
+func (d *document) wordRange(line, column int) Range {
+	text := d.line(line)
+	offset, start := locate(text, column)
+	end := offset
+	for end < len(text) && isIdentifierChar(text[end]) {
+		end++
+	}
+	if end == offset {
+		end++
+	}
+	return Range{
+		Start: Position{Line: line - 1, Character: start},
+		End:   Position{Line: line - 1, Character: start + end - offset},
+	}
+}
+
+// position converts a line and column as the lexer counts them to a
+// position of the protocol.
+func (d *document) position(line, column int) Position {
+	_, character := locate(d.line(line), column)
+	return Position{Line: line - 1, Character: character}
+}
+
+// column converts a position of the protocol to the column the lexer gives
+// the same character.
+func (d *document) column(position Position) int {
+	text := d.line(position.Line + 1)
+	column, units := 1, 0
+	for _, r := range text {
+		if units >= position.Character {
+			break
+		}
+		units += utf16.RuneLen(r)
+		column = advance(column, r)
+	}
+	if units < position.Character {
+		column += position.Character - units
+	}
+	return column
+}
+
+// locate returns the byte offset and the UTF-16 offset of a column in a
+// line. The lexer counts columns a rune at a time with tabs four wide, the
+// protocol counts characters in UTF-16 code units. Columns past the end of
+// the line are one character each.
+func locate(text string, column int) (offset, character int) {
+	current := 1
+	for offset < len(text) && current < column {
+		r, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+		character += utf16.RuneLen(r)
+		current = advance(current, r)
+	}
+	if current < column {
+		offset += column - current
+		character += column - current
+	}
+	return offset, character
+}
+
+// advance moves a column past a rune the way the lexer does.
+func advance(column int, r rune) int {
+	if r == '\t' {
+		return column + 4
+	}
+	return column + 1
+}
+
+// utf16Len returns the length of text in UTF-16 code units.
+func utf16Len(text string) int {
+	length := 0
+	for _, r := range text {
+		length += utf16.RuneLen(r)
+	}
+	return length
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// nameAt returns the qualified name that ends with the identifier at line
+// and column, like p.origin for the cursor on origin in p.origin.x, and its
+// range.
+func (d *document) nameAt(line, column int) ([]string, Range, bool) {
+	for i, tok := range d.tokens {
+		if tok.Type != token.TokMap.Type("id") || tok.Line != line ||
+			column < tok.Column || column > tok.Column+len(tok.Lit) {
+			continue
+		}
+		parts := d.chain(i)
+		first := d.tokens[i-2*(len(parts)-1)]
+		return parts, Range{
+			Start: d.position(first.Line, first.Column),
+			End:   d.position(tok.Line, tok.Column+len(tok.Lit)),
+		}, true
+	}
+	return nil, Range{}, false
+}
+
+// chain returns the names of a qualified name ending with the identifier at
+// index i of the tokens.
+func (d *document) chain(i int) []string {
+	parts := []string{string(d.tokens[i].Lit)}
+	for i >= 2 && d.tokens[i-1].Type == token.TokMap.Type("dot") && d.tokens[i-2].Type == token.TokMap.Type("id") {
+		i -= 2
+		parts = append([]string{string(d.tokens[i].Lit)}, parts...)
+	}
+	return parts
+}
+
+// scopesAt returns the scopes that contain line and column, innermost first.
+func (d *document) scopesAt(line, column int) []string {
+	scopes := make([]string, 0)
+	for i := len(d.scopes) - 1; i >= 0; i-- {
+		if d.scopes[i].Contains(line, column) {
+			scopes = append(scopes, d.scopes[i].Scope)
+		}
+	}
+	return scopes
+}
+
+// lookup resolves a name used at line and column the way the parser did,
+// from the innermost scope outwards. Locals are only visible after their
+// declaration.
+func (d *document) lookup(name string, line, column int) (interface{}, bool) {
+	for _, scope := range d.scopesAt(line, column) {
+		variable, ok := d.symbols.GetScopes()[scope][name].(shared.Variable)
+		if ok && (variable.Line < line || (variable.Line == line && variable.Column <= column)) {
+			return variable, true
+		}
+	}
+
+	symbol, ok := d.symbols.GetGlobalScope()[name]
+	return symbol, ok
+}
+
+// resolve returns the symbol a qualified name stands for, the fields of a
+// struct value are variables. Globals of modules are not resolved.
+func (d *document) resolve(parts []string, line, column int) (interface{}, bool) {
+	symbol, ok := d.lookup(parts[0], line, column)
+	if !ok || len(parts) == 1 {
+		return symbol, ok
+	}
+
+	variable, ok := symbol.(shared.Variable)
+	if !ok {
+		return nil, false
+	}
+	return variable.Field(strings.Join(parts[1:], "."))
+}
+
+func (d *document) definition(line, column int) *Location {
+	parts, _, ok := d.nameAt(line, column)
+	if !ok {
+		return nil
+	}
+	symbol, ok := d.resolve(parts, line, column)
+	if !ok {
+		return nil
+	}
+
+	var name, module string
+	var declLine, declColumn int
+	switch s := symbol.(type) {
+	case shared.Variable:
+		// The fields of a struct are placed at the declaration of its type
+		name, declLine, declColumn = s.Name, s.Line, s.Column
+	case shared.Function:
+		name, module, declLine, declColumn = s.Name, s.Module, s.Line, s.Column
+	case shared.StructDef:
+		name, module, declLine, declColumn = s.Name, s.Module, s.Line, s.Column
+	default:
+		return nil
+	}
+
+	if module != "" {
+		// Modules that can't be read are taken to be ASCII without tabs
+		other := &document{}
+		if text, err := os.ReadFile(module); err == nil {
+			other.lines = strings.Split(string(text), "\n")
+		}
+		start := other.position(declLine, declColumn)
+		end := other.position(declLine, declColumn+len(name))
+		return &Location{URI: pathToURI(module), Range: Range{Start: start, End: end}}
+	}
+	return &Location{URI: d.uri, Range: d.wordRange(declLine, declColumn)}
+}
+
+func (d *document) hover(line, column int) *Hover {
+	parts, nameRange, ok := d.nameAt(line, column)
+	if !ok {
+		return nil
+	}
+
+	var text string
+	if symbol, ok := d.resolve(parts, line, column); ok {
+		text = describe(strings.Join(parts, "."), symbol)
+	} else if builtin, ok := builtins.Lookup(parts[0]); ok && len(parts) == 1 {
+		text = describeBuiltin(builtin)
+	}
+	if text == "" {
+		return nil
+	}
+
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: "```pogo\n" + text + "\n```"},
+		Range:    &nameRange,
+	}
+}
+
+// completion lists the names that can be used at line and column, or the
+// fields of the struct value before a dot.
+func (d *document) completion(line, column int) []CompletionItem {
+	items := make([]CompletionItem, 0)
+
+	before := -1
+	for i, tok := range d.tokens {
+		if tok.Line < line || (tok.Line == line && tok.Column < column) {
+			before = i
+		}
+	}
+	// Skip the part of the name typed so far
+	if before >= 0 && d.tokens[before].Type == token.TokMap.Type("id") &&
+		d.tokens[before].Line == line && column <= d.tokens[before].Column+len(d.tokens[before].Lit) {
+		before--
+	}
+
+	if before >= 1 && d.tokens[before].Type == token.TokMap.Type("dot") && d.tokens[before-1].Type == token.TokMap.Type("id") {
+		symbol, ok := d.resolve(d.chain(before-1), line, column)
+		if variable, isVariable := symbol.(shared.Variable); ok && isVariable {
+			for _, field := range variable.Fields {
+				items = append(items, CompletionItem{Label: field.Name, Kind: CompletionField, Detail: semantic.TypeName(field)})
+			}
+		}
+		return items
+	}
+
+	seen := make(map[string]bool)
+	add := func(item CompletionItem) {
+		if !seen[item.Label] {
+			seen[item.Label] = true
+			items = append(items, item)
+		}
+	}
+
+	for _, scope := range d.scopesAt(line, column) {
+		for name, symbol := range d.symbols.GetScopes()[scope] {
+			variable, ok := symbol.(shared.Variable)
+			if ok && (variable.Line < line || (variable.Line == line && variable.Column < column)) {
+				add(variableItem(name, variable))
+			}
+		}
+	}
+
+	for name, symbol := range d.symbols.GetGlobalScope() {
+		switch s := symbol.(type) {
+		case shared.Variable:
+			add(variableItem(name, s))
+		case shared.Function:
+			add(CompletionItem{Label: name, Kind: CompletionFunction, Detail: describe(name, s)})
+		case shared.StructDef:
+			add(CompletionItem{Label: name, Kind: CompletionStruct, Detail: "type " + name + " struct"})
+		}
+	}
+
+	for _, name := range builtins.Names() {
+		builtin, _ := builtins.Lookup(name)
+		add(CompletionItem{Label: name, Kind: CompletionFunction, Detail: describeBuiltin(builtin)})
+	}
+	for _, keyword := range keywords {
+		add(CompletionItem{Label: keyword, Kind: CompletionKeyword})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Label < items[j].Label })
+	return items
+}
+
+func variableItem(name string, variable shared.Variable) CompletionItem {
+	kind := CompletionVariable
+	if variable.Constant {
+		kind = CompletionConstant
+	}
+	return CompletionItem{Label: name, Kind: kind, Detail: semantic.TypeName(variable)}
+}
+
+// describe writes a symbol the way it is declared.
+func describe(name string, symbol interface{}) string {
+	switch s := symbol.(type) {
+	case shared.Variable:
+		prefix := ""
+		if s.Constant {
+			prefix = "const "
+		} else if s.Ref {
+			prefix = "ref "
+		}
+		return prefix + name + " : " + semantic.TypeName(s)
+	case shared.Function:
+		params := make([]string, len(s.Parameters))
+		for i, param := range s.Parameters {
+			params[i] = describe(param.Name, param)
+		}
+		return "func " + s.Name + "(" + strings.Join(params, ", ") + ")"
+	case shared.StructDef:
+		var b strings.Builder
+		b.WriteString("type " + s.Name + " struct {\n")
+		for _, field := range s.Fields {
+			fieldType := field.Type.String()
+			if field.Type == shared.TypeStruct {
+				fieldType = field.Struct
+			}
+			b.WriteString("    " + field.Name + " : " + fieldType + ";\n")
+		}
+		b.WriteString("}")
+		return b.String()
+	}
+	return ""
+}
+
+func describeBuiltin(builtin builtins.Builtin) string {
+	signatures := make([]string, len(builtin.Signatures))
+	for i, signature := range builtin.Signatures {
+		params := make([]string, len(signature.Parameters))
+		for j, param := range signature.Parameters {
+			params[j] = param.String()
+		}
+		signatures[i] = builtin.Name + "(" + strings.Join(params, ", ") + ") " + signature.Result.String()
+	}
+	return strings.Join(signatures, "\n")
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+func pathToURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
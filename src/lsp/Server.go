@@ -0,0 +1,176 @@
+// Package lsp is a language server for Pogo speaking the Language Server
+// Protocol over a stream, usually stdin and stdout of `pogo lsp`. Documents
+// are parsed again on every change to publish their diagnostics and answer
+// go-to-definition, hover and completion requests.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Server handles the requests of one client, one at a time.
+type Server struct {
+	in        *bufio.Reader
+	out       io.Writer
+	documents map[string]*document // the open documents by URI
+	shutdown  bool
+	err       error // the first failure to write to the client
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		in:        bufio.NewReader(in),
+		out:       out,
+		documents: make(map[string]*document),
+	}
+}
+
+// Serve handles messages until the client sends exit or closes the stream.
+func (s *Server) Serve() error {
+	for {
+		body, err := readMessage(s.in)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.reply(nil, nil, &responseError{Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if req.Method == "exit" {
+			return nil
+		}
+		if err := s.handle(req); err != nil {
+			return err
+		}
+	}
+}
+
+// handle runs a request or a notification, only failures to write to the
+// client are returned.
+func (s *Server) handle(req request) error {
+	result, rpcErr := s.dispatch(req)
+	if s.err != nil || req.ID == nil {
+		return s.err
+	}
+	return s.reply(req.ID, result, rpcErr)
+}
+
+func (s *Server) dispatch(req request) (interface{}, *responseError) {
+	if s.shutdown {
+		return nil, &responseError{Code: codeInvalidRequest, Message: "the server is shutting down"}
+	}
+
+	switch req.Method {
+	case "initialize":
+		var result initializeResult
+		result.Capabilities.TextDocumentSync = textDocumentSyncFull
+		result.Capabilities.DefinitionProvider = true
+		result.Capabilities.HoverProvider = true
+		result.Capabilities.CompletionProvider.TriggerCharacters = []string{"."}
+		result.ServerInfo.Name = "pogo"
+		return result, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		s.update(params.TextDocument.URI, params.TextDocument.Text)
+		return nil, nil
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		s.update(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		return nil, nil
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		delete(s.documents, params.TextDocument.URI)
+		s.publish(params.TextDocument.URI, []Diagnostic{})
+		return nil, nil
+	case "textDocument/definition", "textDocument/hover", "textDocument/completion":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		doc, exists := s.documents[params.TextDocument.URI]
+		if !exists {
+			return nil, nil
+		}
+		line, column := params.Position.Line+1, doc.column(params.Position)
+		switch req.Method {
+		case "textDocument/definition":
+			return doc.definition(line, column), nil
+		case "textDocument/hover":
+			return doc.hover(line, column), nil
+		default:
+			return doc.completion(line, column), nil
+		}
+	}
+
+	if req.ID == nil {
+		// Notifications the server doesn't know can be ignored
+		return nil, nil
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method '%s' is not supported", req.Method)}
+}
+
+// update parses the new text of a document and publishes its diagnostics.
+// While the text has a syntax error, names resolve to the symbols of the
+// last version that parsed.
+func (s *Server) update(uri, text string) {
+	doc := analyze(uri, []byte(text))
+	if previous, exists := s.documents[uri]; exists && !doc.parsed {
+		doc.symbols, doc.scopes, doc.parsed = previous.symbols, previous.scopes, previous.parsed
+	}
+	s.documents[uri] = doc
+	s.publish(uri, doc.diagnostics)
+}
+
+func (s *Server) publish(uri string, diagnostics []Diagnostic) {
+	if s.err != nil {
+		return
+	}
+	s.err = writeMessage(s.out, notification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics},
+	})
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}, rpcErr *responseError) error {
+	resp := response{JSONRPC: "2.0", ID: id, Error: rpcErr}
+	if rpcErr == nil {
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		resp.Result = encoded
+	}
+	return writeMessage(s.out, resp)
+}
+
+func invalidParams(err error) *responseError {
+	return &responseError{Code: codeInvalidParams, Message: err.Error()}
+}
@@ -0,0 +1,171 @@
+package native
+
+import (
+	"fmt"
+	"pogo/src/shared"
+	"sync"
+)
+
+// Value is an argument or result of a native function.
+type Value struct {
+	Type  shared.Type
+	Int   int
+	Float float64
+}
+
+func Int(v int) Value {
+	return Value{Type: shared.TypeInt, Int: v}
+}
+
+func Float(v float64) Value {
+	return Value{Type: shared.TypeFloat, Float: v}
+}
+
+// Void is the result of a native function that returns nothing.
+func Void() Value {
+	return Value{Type: shared.TypeVoid}
+}
+
+// AsFloat returns the value as a float, promoting ints.
+func (v Value) AsFloat() float64 {
+	if v.Type == shared.TypeInt {
+		return float64(v.Int)
+	}
+	return v.Float
+}
+
+func (v Value) String() string {
+	switch v.Type {
+	case shared.TypeInt:
+		return fmt.Sprint(v.Int)
+	case shared.TypeFloat:
+		return fmt.Sprint(v.Float)
+	}
+	return v.Type.String()
+}
+
+// Func is the host implementation of a native function.
+type Func func(args []Value) (Value, error)
+
+// Signature describes the parameters of a native function and the type of
+// its result, shared.TypeVoid when it returns nothing. Int arguments are
+// accepted for float parameters.
+type Signature struct {
+	Parameters []shared.Type
+	Result     shared.Type
+}
+
+type Function struct {
+	Name      string
+	Signature Signature
+	Impl      Func
+}
+
+// Registry holds the native functions of one host. The same registry must
+// be used to compile a program and to run it. It is safe for concurrent use,
+// functions can be registered while programs using it compile and run.
+type Registry struct {
+	mu        sync.RWMutex
+	functions map[string]Function
+}
+
+func NewRegistry() *Registry {
+	return &Registry{functions: make(map[string]Function)}
+}
+
+// RegisterNative makes impl callable from Pogo as name.
+func (r *Registry) RegisterNative(name string, impl Func, signature Signature) error {
+	if name == "" || impl == nil {
+		return fmt.Errorf("native function needs a name and an implementation")
+	}
+	for _, param := range signature.Parameters {
+		if param != shared.TypeInt && param != shared.TypeFloat {
+			return fmt.Errorf("native function '%s': unsupported parameter type %v", name, param)
+		}
+	}
+	switch signature.Result {
+	case shared.TypeInt, shared.TypeFloat, shared.TypeVoid:
+	default:
+		return fmt.Errorf("native function '%s': unsupported result type %v", name, signature.Result)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.functions[name]; exists {
+		return fmt.Errorf("native function '%s' already registered", name)
+	}
+	r.functions[name] = Function{Name: name, Signature: signature, Impl: impl}
+	return nil
+}
+
+// Lookup returns the native function registered as name, a nil registry
+// has none.
+func (r *Registry) Lookup(name string) (Function, bool) {
+	if r == nil {
+		return Function{}, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	function, exists := r.functions[name]
+	return function, exists
+}
+
+// Accepts reports whether arguments of the given types can be passed.
+func (s Signature) Accepts(args []shared.Type) bool {
+	if len(args) != len(s.Parameters) {
+		return false
+	}
+	for i, param := range s.Parameters {
+		if args[i] != param && !(param == shared.TypeFloat && args[i] == shared.TypeInt) {
+			return false
+		}
+	}
+	return true
+}
+
+// Call marshals values loaded by the virtual machine into Values, calls the
+// function and converts its result back. Mismatched types are errors.
+func (f Function) Call(args []interface{}) (interface{}, error) {
+	if len(args) != len(f.Signature.Parameters) {
+		return nil, fmt.Errorf("native '%s' expects %d arguments but got %d", f.Name, len(f.Signature.Parameters), len(args))
+	}
+
+	values := make([]Value, len(args))
+	for i, arg := range args {
+		param := f.Signature.Parameters[i]
+		switch v := arg.(type) {
+		case int:
+			values[i] = Int(v)
+			if param == shared.TypeFloat {
+				values[i] = Float(float64(v))
+			}
+		case float64:
+			if param != shared.TypeFloat {
+				return nil, fmt.Errorf("native '%s': argument %d is a float, expected %v", f.Name, i, param)
+			}
+			values[i] = Float(v)
+		default:
+			return nil, fmt.Errorf("native '%s': unsupported argument type %T", f.Name, arg)
+		}
+	}
+
+	result, err := f.Impl(values)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", f.Name, err)
+	}
+
+	switch {
+	case f.Signature.Result == shared.TypeVoid:
+		return nil, nil
+	case result.Type == shared.TypeInt && f.Signature.Result == shared.TypeInt:
+		return result.Int, nil
+	case result.Type == shared.TypeInt && f.Signature.Result == shared.TypeFloat:
+		return float64(result.Int), nil
+	case result.Type == shared.TypeFloat && f.Signature.Result == shared.TypeFloat:
+		return result.Float, nil
+	}
+	return nil, fmt.Errorf("native '%s' returned %v, expected %v", f.Name, result.Type, f.Signature.Result)
+}
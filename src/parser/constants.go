@@ -0,0 +1,180 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"pogo/src/ast"
+	"pogo/src/semantic"
+	"pogo/src/shared"
+	"pogo/src/token"
+	"strconv"
+)
+
+func (p *Parser) parseConstDeclaration() (*ast.ConstDecl, error) {
+	decl := &ast.ConstDecl{Pos: p.position()}
+	if err := p.expect(token.TokMap.Type("kwdConst")); err != nil {
+		return nil, err
+	}
+
+	for {
+		spec, err := p.parseConstSpec()
+		if err != nil {
+			return nil, err
+		}
+		decl.Specs = append(decl.Specs, spec)
+
+		if p.curr.Type != token.TokMap.Type("repeatTerminator") {
+			break
+		}
+		p.next()
+	}
+
+	return decl, p.expect(token.TokMap.Type("terminator"))
+}
+
+func (p *Parser) parseConstSpec() (*ast.ConstSpec, error) {
+	spec := &ast.ConstSpec{Pos: p.position()}
+
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	spec.Name = name
+
+	if err := p.expect(token.TokMap.Type("typeAssignOp")); err != nil {
+		return nil, err
+	}
+	spec.Type = &ast.TypeName{Pos: p.position(), Name: string(p.curr.Lit)}
+	if _, err := p.parseType(); err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(token.TokMap.Type("assignOp")); err != nil {
+		return nil, err
+	}
+	if spec.Value, err = p.parseExpression(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// genConstDeclaration evaluates the value of every constant and stores it
+// in the constant pool, reading a constant just reads that address.
+func (p *Parser) genConstDeclaration(decl *ast.ConstDecl) error {
+	for _, spec := range decl.Specs {
+		p.pos = spec.Pos
+
+		semType, err := p.semanticType(spec.Type)
+		if err != nil {
+			return err
+		}
+
+		value, err := p.constant(spec.Value)
+		if err != nil {
+			return err
+		}
+
+		value, err = p.CodeGenerator.SemanticCube.ConvertTo(value, semType)
+		if err != nil {
+			return errorAt(spec.Name, err)
+		}
+
+		addr, err := p.CodeGenerator.MemoryManager.AllocateConstant(value.Literal())
+		if err != nil {
+			return err
+		}
+
+		if err := p.SymbolTable.AddConstant(spec.Name.Name, semType, spec.Name.Line, spec.Name.Column, addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// constant evaluates an arithmetic expression made of literals and
+// previously declared constants.
+func (p *Parser) constant(expr ast.Expr) (semantic.Constant, error) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return p.constant(e.X)
+	case *ast.BinaryExpr:
+		left, err := p.constant(e.X)
+		if err != nil {
+			return semantic.Constant{}, err
+		}
+		right, err := p.constant(e.Y)
+		if err != nil {
+			return semantic.Constant{}, err
+		}
+
+		value, err := p.CodeGenerator.SemanticCube.Fold(left, e.Op, right)
+		if err != nil {
+			return semantic.Constant{}, errorAt(e, err)
+		}
+		return value, nil
+	case *ast.UnaryExpr:
+		value, err := p.constant(e.X)
+		if err != nil || e.Op == "+" {
+			return value, err
+		}
+		return p.CodeGenerator.SemanticCube.Fold(semantic.IntConstant(-1), "*", value)
+	case *ast.BasicLit:
+		switch e.Type {
+		case shared.TypeInt:
+			value, err := strconv.Atoi(e.Value)
+			if errors.Is(err, strconv.ErrRange) {
+				return semantic.Constant{}, fmt.Errorf("line %d: int literal '%s' out of range", e.Line, e.Value)
+			}
+			if err != nil {
+				return semantic.Constant{}, fmt.Errorf("line %d: invalid int literal '%s'", e.Line, e.Value)
+			}
+			return semantic.IntConstant(value), nil
+		case shared.TypeFloat:
+			value, err := strconv.ParseFloat(e.Value, 64)
+			if err != nil {
+				return semantic.Constant{}, fmt.Errorf("line %d: invalid float literal '%s'", e.Line, e.Value)
+			}
+			return semantic.FloatConstant(value), nil
+		}
+	case *ast.CallExpr:
+		if !p.isConversion(e) {
+			break
+		}
+		if len(e.Args) != 1 {
+			return semantic.Constant{}, fmt.Errorf("line %d: %s expects one argument, got %d", e.Line, e.Func.Name, len(e.Args))
+		}
+
+		value, err := p.constant(e.Args[0])
+		if err != nil {
+			return semantic.Constant{}, err
+		}
+		if value, err = p.CodeGenerator.SemanticCube.FoldConversion(e.Func.Name, value); err != nil {
+			return semantic.Constant{}, errorAt(e, err)
+		}
+		return value, nil
+	case *ast.Name:
+		constant, err := p.SymbolTable.GetConstant(e.Name)
+		if err != nil {
+			return semantic.Constant{}, fmt.Errorf("line %d: %v, constant expressions can only use literals and constants", e.Line, err)
+		}
+		value, _ := p.CodeGenerator.MemoryManager.GetConstant(constant.Address)
+		return semantic.Constant{Type: constant.Type, Value: value}, nil
+	}
+
+	return semantic.Constant{}, fmt.Errorf("line %d: constant expressions can only use literals and constants",
+		expr.Position().Line)
+}
+
+// intConstant evaluates a constant expression that must be an int, e.g. a
+// case label.
+func (p *Parser) intConstant(expr ast.Expr) (int, error) {
+	value, err := p.constant(expr)
+	if err != nil {
+		return 0, err
+	}
+
+	if value.Type != shared.TypeInt {
+		return 0, fmt.Errorf("line %d: expected an int constant, got %v", expr.Position().Line, value.Type)
+	}
+	return value.Value.(int), nil
+}
@@ -0,0 +1,279 @@
+package parser
+
+import (
+	"fmt"
+	"pogo/src/ast"
+	"pogo/src/shared"
+	"pogo/src/token"
+)
+
+// parseExpression parses a comparison, the operators of lower precedence
+// are parsed by the functions it calls. Every binary operator but '**'
+// groups to the left.
+func (p *Parser) parseExpression() (ast.Expr, error) {
+	return p.parseBinary("relOp", p.parseExp)
+}
+
+func (p *Parser) parseExp() (ast.Expr, error) {
+	return p.parseBinary("expressionOp", p.parseTerm)
+}
+
+func (p *Parser) parseTerm() (ast.Expr, error) {
+	return p.parseBinary("termOp", p.parsePower)
+}
+
+// parseBinary parses operands joined by operators of the token type op.
+func (p *Parser) parseBinary(op string, parseOperand func() (ast.Expr, error)) (ast.Expr, error) {
+	left, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.curr.Type == token.TokMap.Type(op) {
+		expr := &ast.BinaryExpr{Pos: p.position(), Op: string(p.curr.Lit), X: left}
+		p.next()
+
+		if expr.Y, err = parseOperand(); err != nil {
+			return nil, err
+		}
+		left = expr
+	}
+	return left, nil
+}
+
+// parsePower parses '**', which binds tighter than the term operators and
+// groups to the right: 2 ** 3 ** 2 is 2 ** 9.
+func (p *Parser) parsePower() (ast.Expr, error) {
+	base, err := p.parseFactor()
+	if err != nil || p.curr.Type != token.TokMap.Type("powOp") {
+		return base, err
+	}
+
+	expr := &ast.BinaryExpr{Pos: p.position(), Op: string(p.curr.Lit), X: base}
+	p.next()
+
+	if expr.Y, err = p.parsePower(); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+func (p *Parser) parseFactor() (ast.Expr, error) {
+	pos := p.position()
+
+	switch p.curr.Type {
+	case token.TokMap.Type("openParan"):
+		p.next()
+		x, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		return &ast.ParenExpr{Pos: pos, X: x}, p.expect(token.TokMap.Type("closeParan"))
+	case token.TokMap.Type("expressionOp"):
+		op := string(p.curr.Lit)
+		p.next()
+		x, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return &ast.UnaryExpr{Pos: pos, Op: op, X: x}, nil
+	case token.TokMap.Type("type"):
+		// A cast, int(x) or float(x)
+		ident := &ast.Ident{Pos: pos, Name: string(p.curr.Lit)}
+		p.next()
+		return p.parseCall(ident)
+	case token.TokMap.Type("id"):
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		if p.curr.Type != token.TokMap.Type("openParan") {
+			return name, nil
+		}
+		return p.parseCall(&ast.Ident{Pos: name.Pos, Name: name.Name})
+	case token.TokMap.Type("intLit"), token.TokMap.Type("floatLit"):
+		lit := &ast.BasicLit{Pos: pos, Type: shared.TypeInt, Value: string(p.curr.Lit)}
+		if p.curr.Type == token.TokMap.Type("floatLit") {
+			lit.Type = shared.TypeFloat
+		}
+		p.next()
+		return lit, nil
+	default:
+		return nil, fmt.Errorf("unexpected token in factor: %v in line %v and %v",
+			token.TokMap.Id(p.curr.Type), p.curr.Line, string(p.curr.Lit))
+	}
+}
+
+// parseCall parses the arguments of a call to the function named by ident.
+func (p *Parser) parseCall(ident *ast.Ident) (*ast.CallExpr, error) {
+	call := &ast.CallExpr{Pos: ident.Pos, Func: ident, Args: make([]ast.Expr, 0)}
+	if err := p.expect(token.TokMap.Type("openParan")); err != nil {
+		return nil, err
+	}
+
+	for p.curr.Type != token.TokMap.Type("closeParan") {
+		arg, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		call.Args = append(call.Args, arg)
+
+		if p.curr.Type != token.TokMap.Type("repeatTerminator") {
+			break
+		}
+		p.next()
+	}
+
+	return call, p.expect(token.TokMap.Type("closeParan"))
+}
+
+// genExpression generates the code of an expression, its value is left on
+// top of the operand stack. It returns the type of the value.
+func (p *Parser) genExpression(expr ast.Expr) (shared.Type, error) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Type == shared.TypeString {
+			return shared.TypeError, fmt.Errorf("line %d: string literals can only be printed", e.Line)
+		}
+		if err := p.CodeGenerator.HandleLiteral(e.Value, e.Type); err != nil {
+			return shared.TypeError, errorAt(e, err)
+		}
+		return e.Type, nil
+	case *ast.Name:
+		return p.genName(e)
+	case *ast.ParenExpr:
+		return p.genExpression(e.X)
+	case *ast.UnaryExpr:
+		// A negative literal is a constant of its own
+		if lit, ok := e.X.(*ast.BasicLit); ok && lit.Type != shared.TypeString {
+			value := lit.Value
+			if e.Op == "-" {
+				value = "-" + value
+			}
+			if err := p.CodeGenerator.HandleLiteral(value, lit.Type); err != nil {
+				return shared.TypeError, errorAt(e, err)
+			}
+			return lit.Type, nil
+		}
+
+		valueType, err := p.genExpression(e.X)
+		if err != nil || e.Op != "-" {
+			return valueType, err
+		}
+		return valueType, p.CodeGenerator.HandleNegation()
+	case *ast.BinaryExpr:
+		if _, err := p.genExpression(e.X); err != nil {
+			return shared.TypeError, err
+		}
+		if _, err := p.genExpression(e.Y); err != nil {
+			return shared.TypeError, err
+		}
+		if err := p.CodeGenerator.HandleBinaryOp(e.Op); err != nil {
+			return shared.TypeError, errorAt(e, err)
+		}
+		return p.CodeGenerator.TypeStack.Top().(shared.Type), nil
+	case *ast.CallExpr:
+		return p.genCallExpression(e)
+	}
+	return shared.TypeError, fmt.Errorf("line %d: unexpected expression %T", expr.Position().Line, expr)
+}
+
+// genName pushes the value of a variable, a field or a constant.
+func (p *Parser) genName(name *ast.Name) (shared.Type, error) {
+	nameType, err := p.SymbolTable.GetType(name.Name)
+	if err != nil {
+		return shared.TypeError, errorAt(name, err)
+	}
+	if nameType == shared.TypeStruct {
+		return shared.TypeError, fmt.Errorf("line %d: '%s' is a struct, only its fields can be used in expressions", name.Line, name.Name)
+	}
+
+	addr, err := p.SymbolTable.GetVariableAddress(name.Name)
+	if err != nil {
+		return shared.TypeError, errorAt(name, err)
+	}
+
+	p.CodeGenerator.HandleOperand(addr, nameType)
+	return nameType, nil
+}
+
+// genCallExpression generates a conversion or a call to a builtin used as a
+// value, functions don't return values.
+func (p *Parser) genCallExpression(call *ast.CallExpr) (shared.Type, error) {
+	if p.isConversion(call) {
+		return p.genConversion(call)
+	}
+
+	if !p.isHostCall(call) {
+		if _, err := p.SymbolTable.GetType(call.Func.Name); err != nil {
+			if _, isFunction := p.SymbolTable.GetGlobalScope()[call.Func.Name].(shared.Function); isFunction {
+				return shared.TypeError, fmt.Errorf("line %d: function '%s' does not return a value", call.Line, call.Func.Name)
+			}
+			return shared.TypeError, errorAt(call, err)
+		}
+		return shared.TypeError, fmt.Errorf("line %d: '%s' is not a function", call.Line, call.Func.Name)
+	}
+
+	resultType, err := p.genHostCall(call)
+	if err != nil {
+		return shared.TypeError, err
+	}
+
+	if resultType == shared.TypeVoid {
+		return shared.TypeError, fmt.Errorf("line %d: builtin '%s' does not return a value", call.Line, call.Func.Name)
+	}
+	return resultType, nil
+}
+
+// genHostCall generates the arguments of a call to a Go builtin or a host
+// native function and emits the call.
+func (p *Parser) genHostCall(call *ast.CallExpr) (shared.Type, error) {
+	arguments := make([]int, 0, len(call.Args))
+	argumentTypes := make([]shared.Type, 0, len(call.Args))
+	for _, arg := range call.Args {
+		if _, err := p.genExpression(arg); err != nil {
+			return shared.TypeError, err
+		}
+
+		arguments = append(arguments, p.CodeGenerator.OperandStack.Pop().(int))
+		argumentTypes = append(argumentTypes, p.CodeGenerator.TypeStack.Pop().(shared.Type))
+	}
+
+	name := call.Func.Name
+	if p.SymbolTable.IsNative(name) {
+		signature, err := p.SymbolTable.ValidateNativeCall(name, call.Line, argumentTypes)
+		if err != nil {
+			return shared.TypeError, err
+		}
+		return signature.Result, p.CodeGenerator.HandleNativeCall(name, arguments, signature.Result)
+	}
+
+	signature, err := p.SymbolTable.ValidateBuiltinCall(name, call.Line, argumentTypes)
+	if err != nil {
+		return shared.TypeError, err
+	}
+
+	if err := p.CodeGenerator.HandleBuiltinCall(name, arguments, signature.Result); err != nil {
+		return shared.TypeError, err
+	}
+
+	return signature.Result, nil
+}
+
+// genConversion generates a cast such as int(x) or float(n), or one of the
+// rounding builtins round(x), floor(x) and ceil(x).
+func (p *Parser) genConversion(call *ast.CallExpr) (shared.Type, error) {
+	if len(call.Args) != 1 {
+		return shared.TypeError, fmt.Errorf("line %d: %s expects one argument, got %d", call.Line, call.Func.Name, len(call.Args))
+	}
+
+	if _, err := p.genExpression(call.Args[0]); err != nil {
+		return shared.TypeError, err
+	}
+
+	if err := p.CodeGenerator.HandleConversion(call.Func.Name); err != nil {
+		return shared.TypeError, errorAt(call, err)
+	}
+
+	return p.CodeGenerator.TypeStack.Top().(shared.Type), nil
+}
@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"fmt"
+	"pogo/src/ast"
+	"pogo/src/semantic"
+	"pogo/src/shared"
+)
+
+// genFunctions declares the signature of every function of the file before
+// the code of any of them is generated, so a function can call the
+// functions that come after it.
+func (p *Parser) genFunctions(functions []*ast.FuncDecl) error {
+	if len(functions) == 0 {
+		return nil
+	}
+
+	for _, function := range functions {
+		if err := p.declareFunction(function); err != nil {
+			return err
+		}
+	}
+
+	for _, function := range functions {
+		if err := p.genFunction(function); err != nil {
+			return err
+		}
+	}
+
+	return p.resolveCalls()
+}
+
+// declareFunction declares a function, its parameters get the same slots
+// they get when its code is generated.
+func (p *Parser) declareFunction(function *ast.FuncDecl) error {
+	p.pos = function.Pos
+
+	p.CodeGenerator.MemoryManager.PushNewFunctionSegment(true, 0, 0)
+	params, err := p.genParameters(function.Params)
+	if err != nil {
+		return err
+	}
+	if err := p.CodeGenerator.MemoryManager.PopFunctionSegment(); err != nil {
+		return err
+	}
+
+	return p.SymbolTable.AddFunction(function.Name.Name, params, function.Name.Line, function.Name.Column)
+}
+
+func (p *Parser) genFunction(function *ast.FuncDecl) error {
+	defer p.markPosition(len(p.CodeGenerator.Quads), function)
+	p.pos = function.Pos
+	name := function.Name.Name
+
+	p.CodeGenerator.MemoryManager.PushNewFunctionSegment(true, 0, 0)
+
+	// declareFunction already declared the function, laying the parameters
+	// out again gives them their slots in the segment of the function
+	if _, err := p.genParameters(function.Params); err != nil {
+		return err
+	}
+
+	if err := p.SymbolTable.EnterFunctionScope(name); err != nil {
+		return err
+	}
+	scope := p.openScope(name, function.Pos)
+
+	functionStartQuad := len(p.CodeGenerator.Quads)
+	if err := p.SymbolTable.UpdateFunctionStartQuad(name, functionStartQuad); err != nil {
+		return err
+	}
+
+	// The outermost block of a function shares the function scope, so its
+	// declarations cannot redeclare a parameter
+	if err := p.genStatements(function.Body.Stmts); err != nil {
+		return err
+	}
+
+	if err := p.CodeGenerator.HandleENDPROC(); err != nil {
+		return err
+	}
+
+	if err := p.CodeGenerator.MemoryManager.PopFunctionSegment(); err != nil {
+		return err
+	}
+
+	p.closeScope(scope, function.End)
+	p.SymbolTable.ExitFunctionScope()
+	return nil
+}
+
+// genParameters gives every parameter a local slot, a struct parameter gets
+// one for each of its fields. A ref parameter holds a reference to the
+// argument in each of its slots.
+func (p *Parser) genParameters(params []*ast.Param) ([]shared.Variable, error) {
+	variables := make([]shared.Variable, 0, len(params))
+	for _, param := range params {
+		semType, err := p.semanticType(param.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		variable := shared.Variable{
+			Name:   param.Name.Name,
+			Type:   semType,
+			Line:   param.Name.Line,
+			Column: param.Name.Column,
+			Ref:    param.Ref,
+		}
+
+		if semType == shared.TypeStruct {
+			variable.Struct = param.Type.Name
+			variable.Address = -1
+			variable.Fields, err = p.SymbolTable.LayoutStruct(variable.Struct, p.CodeGenerator.MemoryManager.AllocateLocal)
+		} else {
+			variable.Address, err = p.CodeGenerator.MemoryManager.AllocateLocal(semType)
+		}
+		if err != nil {
+			return nil, err
+		}
+		variables = append(variables, variable)
+	}
+	return variables, nil
+}
+
+// resolveCalls points the calls to functions generated after the caller to
+// the start of the function. Calls to the functions of modules compiled on
+// their own stay unresolved until the objects are linked.
+func (p *Parser) resolveCalls() error {
+	for i, quad := range p.CodeGenerator.Quads {
+		if quad.Operator != "gosub" || quad.Result.(int) >= 0 {
+			continue
+		}
+
+		name := quad.LeftOp.(string)
+		function, ok := p.SymbolTable.GetGlobalScope()[name].(shared.Function)
+		if !ok {
+			return fmt.Errorf("line %d: call to undefined function '%s'", quad.Line, name)
+		}
+		if function.Module != "" {
+			continue
+		}
+		if function.StartQuad < 0 {
+			return fmt.Errorf("line %d: function '%s' is declared but never defined", quad.Line, name)
+		}
+		p.CodeGenerator.Quads[i].Result = function.StartQuad
+	}
+	return nil
+}
+
+// genMain generates the main section, its variables live in its own
+// activation record, so functions can't see them.
+func (p *Parser) genMain(main *ast.Block) error {
+	p.pos = main.Pos
+	p.CodeGenerator.HandleMainStart()
+
+	p.CodeGenerator.HandleMainFrame()
+	p.SymbolTable.EnterMainScope()
+	scope := p.openScope(semantic.MainScope, main.Pos)
+	p.CodeGenerator.MemoryManager.PushNewFunctionSegment(true, 0, 0)
+
+	if err := p.genStatements(main.Stmts); err != nil {
+		return err
+	}
+
+	p.closeScope(scope, main.End)
+	p.CodeGenerator.HandleMainEnd(p.SymbolTable.GetMainVarCounts())
+	p.SymbolTable.ExitMainScope()
+	return p.CodeGenerator.MemoryManager.PopFunctionSegment()
+}
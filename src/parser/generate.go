@@ -0,0 +1,394 @@
+package parser
+
+import (
+	"fmt"
+	"pogo/src/ast"
+	"pogo/src/shared"
+)
+
+// genProgram generates the code of a parsed program or module, declaring
+// its symbols in the order they appear.
+func (p *Parser) genProgram(program *ast.Program) error {
+	if err := p.genImports(program.Imports); err != nil {
+		return err
+	}
+
+	// Global initializers run before the jump to main
+	if err := p.genDeclarations(program.Decls); err != nil {
+		return err
+	}
+
+	// The jump over the functions, patched once they are generated
+	p.CodeGenerator.HandleProgramStart()
+
+	if err := p.genFunctions(program.Funcs); err != nil {
+		return err
+	}
+
+	if program.Module {
+		p.CodeGenerator.HandleMainStart()
+		return nil
+	}
+	return p.genMain(program.Main)
+}
+
+func (p *Parser) genDeclarations(decls []ast.Decl) error {
+	for _, decl := range decls {
+		var err error
+		switch d := decl.(type) {
+		case *ast.VarDecl:
+			err = p.genVarDeclaration(d)
+		case *ast.ConstDecl:
+			err = p.genConstDeclaration(d)
+		case *ast.TypeDecl:
+			err = p.genTypeDeclaration(d)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Parser) genVarDeclaration(decl *ast.VarDecl) error {
+	for _, group := range decl.Groups {
+		if err := p.genVarGroup(group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// genVarGroup declares the variables of a group. The initializer is
+// generated before the group is declared, so it can only refer to variables
+// that already exist (var x : int = x uses the outer x).
+func (p *Parser) genVarGroup(group *ast.VarGroup) error {
+	defer p.markPosition(len(p.CodeGenerator.Quads), group)
+	p.pos = group.Pos
+
+	semType, err := p.semanticType(group.Type)
+	if err != nil {
+		return err
+	}
+
+	if semType == shared.TypeStruct {
+		if group.Value != nil {
+			return fmt.Errorf("line %d: variables of type %s cannot have an initializer", group.Value.Position().Line, group.Type.Name)
+		}
+		return p.addStructVariablesToSymbolTable(group.Type.Name, group.Names)
+	}
+
+	if group.Value != nil {
+		if _, err := p.genExpression(group.Value); err != nil {
+			return err
+		}
+	}
+
+	addresses, err := p.addVariablesToSymbolTable(semType, group.Names)
+	if err != nil {
+		return err
+	}
+
+	if group.Value != nil {
+		if err := p.CodeGenerator.HandleInitialization(addresses, semType); err != nil {
+			return errorAt(group.Value, err)
+		}
+	}
+	return nil
+}
+
+// genBlock generates a block in a scope of its own.
+func (p *Parser) genBlock(block *ast.Block) error {
+	scope := p.openScope(p.SymbolTable.EnterBlockScope(), block.Pos)
+
+	if err := p.genStatements(block.Stmts); err != nil {
+		return err
+	}
+
+	p.SymbolTable.ExitBlockScope()
+	p.closeScope(scope, block.End)
+	return nil
+}
+
+// genStatements generates the statements of a block, var and const
+// declarations are visible until the end of the block.
+func (p *Parser) genStatements(stmts []ast.Stmt) error {
+	for _, stmt := range stmts {
+		if err := p.genStatement(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Parser) genStatement(stmt ast.Stmt) error {
+	defer p.markPosition(len(p.CodeGenerator.Quads), stmt)
+	p.pos = stmt.Position()
+
+	switch s := stmt.(type) {
+	case *ast.VarDecl:
+		return p.genVarDeclaration(s)
+	case *ast.ConstDecl:
+		return p.genConstDeclaration(s)
+	case *ast.Block:
+		return p.genBlock(s)
+	case *ast.AssignStmt:
+		return p.genAssignment(s)
+	case *ast.CallStmt:
+		return p.genCallStatement(s.Call)
+	case *ast.PrintStmt:
+		return p.genPrint(s)
+	case *ast.IfStmt:
+		p.CodeGenerator.HandleIfChainStart()
+		if err := p.genIf(s); err != nil {
+			return err
+		}
+		return p.CodeGenerator.HandleIfChainEnd()
+	case *ast.WhileStmt:
+		return p.genWhile(s)
+	case *ast.SwitchStmt:
+		return p.genSwitch(s)
+	}
+	return fmt.Errorf("line %d: unexpected statement %T", p.pos.Line, stmt)
+}
+
+func (p *Parser) genAssignment(stmt *ast.AssignStmt) error {
+	target := stmt.Target
+	if err := p.SymbolTable.ValidateVarAssignment(target.Name, target.Line); err != nil {
+		return err
+	}
+
+	if targetType, _ := p.SymbolTable.GetType(target.Name); targetType == shared.TypeStruct {
+		return p.genStructAssignment(stmt)
+	}
+
+	if _, err := p.genExpression(stmt.Value); err != nil {
+		return err
+	}
+
+	currType, err := p.SymbolTable.GetType(target.Name)
+	if err != nil {
+		return errorAt(target, err)
+	}
+
+	targetAddr, err := p.SymbolTable.GetVariableAddress(target.Name)
+	if err != nil {
+		return errorAt(target, err)
+	}
+
+	if err := p.CodeGenerator.HandleAssignment(targetAddr, currType); err != nil {
+		return errorAt(target, err)
+	}
+	return nil
+}
+
+// genCallStatement calls a function, a builtin called as a statement
+// discards its result.
+func (p *Parser) genCallStatement(call *ast.CallExpr) error {
+	name := call.Func.Name
+
+	if p.isHostCall(call) {
+		resultType, err := p.genHostCall(call)
+		if err == nil && resultType != shared.TypeVoid {
+			p.CodeGenerator.OperandStack.Pop()
+			p.CodeGenerator.TypeStack.Pop()
+		}
+		return err
+	}
+
+	if err := p.CodeGenerator.HandleERA(name); err != nil {
+		return err
+	}
+
+	arguments, err := p.genArguments(call.Args, p.SymbolTable.GetFunctionParameters(name))
+	if err != nil {
+		return err
+	}
+
+	if err := p.SymbolTable.ValidateFunctionCall(name, call.Line, arguments); err != nil {
+		return err
+	}
+
+	startQuad, err := p.SymbolTable.GetFunctionStartQuad(name)
+	if err != nil {
+		return errorAt(call, err)
+	}
+
+	return p.CodeGenerator.HandleGOSUB(name, startQuad)
+}
+
+// genArguments passes the arguments of a call to a function with params, a
+// struct argument fills a parameter slot per field.
+func (p *Parser) genArguments(args []ast.Expr, params []shared.Variable) ([]shared.Argument, error) {
+	paramCount := 0
+	arguments := make([]shared.Argument, 0, len(args))
+	for index, arg := range args {
+		switch {
+		case index < len(params) && params[index].Ref:
+			argument, slots, err := p.genRefArgument(arg, params[index], paramCount)
+			if err != nil {
+				return nil, err
+			}
+			paramCount += slots
+			arguments = append(arguments, argument)
+		case index < len(params) && params[index].Type == shared.TypeStruct:
+			slots, err := p.genStructArgument(arg, params[index], paramCount)
+			if err != nil {
+				return nil, err
+			}
+			paramCount += slots
+			arguments = append(arguments, shared.Argument{Type: shared.TypeStruct})
+		default:
+			argType, err := p.genExpression(arg)
+			if err != nil {
+				return nil, err
+			}
+
+			value := p.CodeGenerator.OperandStack.Pop()
+			p.CodeGenerator.TypeStack.Pop()
+			if err := p.CodeGenerator.HandleParam(value, paramCount); err != nil {
+				return nil, err
+			}
+			paramCount++
+			arguments = append(arguments, shared.Argument{Type: argType})
+		}
+	}
+	return arguments, nil
+}
+
+// genRefArgument passes the argument of a parameter passed by reference, it
+// must name a variable, a field or a module global. It returns the argument
+// and the number of parameter slots it fills.
+func (p *Parser) genRefArgument(arg ast.Expr, param shared.Variable, first int) (shared.Argument, int, error) {
+	name, ok := arg.(*ast.Name)
+	if !ok {
+		return shared.Argument{}, 0, fmt.Errorf("line %d: argument for parameter '%s' must be a variable, it is passed by reference",
+			arg.Position().Line, param.Name)
+	}
+
+	variable, err := p.SymbolTable.GetVariable(name.Name)
+	if err != nil {
+		return shared.Argument{}, 0, errorAt(name, err)
+	}
+
+	slots := p.CodeGenerator.HandleRefParam(variable, first)
+	return shared.Argument{Type: variable.Type, Variable: &variable}, slots, nil
+}
+
+func (p *Parser) genPrint(stmt *ast.PrintStmt) error {
+	items := make([]interface{}, 0, len(stmt.Items))
+	for _, item := range stmt.Items {
+		if lit, ok := item.(*ast.BasicLit); ok && lit.Type == shared.TypeString {
+			items = append(items, lit.Value)
+			continue
+		}
+
+		if _, err := p.genExpression(item); err != nil {
+			return err
+		}
+		items = append(items, p.CodeGenerator.OperandStack.Pop())
+		p.CodeGenerator.TypeStack.Pop()
+	}
+
+	return p.CodeGenerator.HandlePrint(items)
+}
+
+// genIf generates a branch of an if chain, the branches of its else ifs
+// share the exit jumps of the chain.
+func (p *Parser) genIf(stmt *ast.IfStmt) error {
+	if _, err := p.genExpression(stmt.Cond); err != nil {
+		return err
+	}
+
+	if err := p.CodeGenerator.HandleIfStatement(); err != nil {
+		return err
+	}
+
+	if err := p.genBlock(stmt.Then); err != nil {
+		return err
+	}
+
+	if stmt.Else == nil {
+		return p.CodeGenerator.HandleEndIf()
+	}
+
+	if err := p.CodeGenerator.HandleElse(); err != nil {
+		return err
+	}
+
+	switch e := stmt.Else.(type) {
+	case *ast.IfStmt:
+		defer p.markPosition(len(p.CodeGenerator.Quads), e)
+		p.pos = e.Pos
+		return p.genIf(e)
+	case *ast.Block:
+		return p.genBlock(e)
+	}
+	return nil
+}
+
+func (p *Parser) genWhile(stmt *ast.WhileStmt) error {
+	startIndex := p.CodeGenerator.HandleWhileStart()
+
+	if _, err := p.genExpression(stmt.Cond); err != nil {
+		return err
+	}
+
+	if err := p.CodeGenerator.HandleWhileCondition(); err != nil {
+		return err
+	}
+
+	if err := p.genBlock(stmt.Body); err != nil {
+		return err
+	}
+
+	return p.CodeGenerator.HandleWhileEnd(startIndex)
+}
+
+func (p *Parser) genSwitch(stmt *ast.SwitchStmt) error {
+	if _, err := p.genExpression(stmt.Tag); err != nil {
+		return err
+	}
+
+	if err := p.CodeGenerator.HandleSwitchStart(); err != nil {
+		return errorAt(stmt.Tag, err)
+	}
+
+	for _, clause := range stmt.Cases {
+		values := make([]int, 0, len(clause.Values))
+		for _, expr := range clause.Values {
+			value, err := p.intConstant(expr)
+			if err != nil {
+				return err
+			}
+			values = append(values, value)
+		}
+
+		if err := p.CodeGenerator.HandleCase(values); err != nil {
+			return errorAt(clause, err)
+		}
+
+		if err := p.genCaseBody(clause); err != nil {
+			return err
+		}
+	}
+
+	if stmt.Default != nil {
+		if err := p.CodeGenerator.HandleDefault(); err != nil {
+			return errorAt(stmt.Default, err)
+		}
+
+		if err := p.genCaseBody(stmt.Default); err != nil {
+			return err
+		}
+	}
+
+	return p.CodeGenerator.HandleSwitchEnd()
+}
+
+func (p *Parser) genCaseBody(clause *ast.CaseClause) error {
+	if err := p.genStatements(clause.Body); err != nil {
+		return err
+	}
+	return p.CodeGenerator.HandleCaseEnd()
+}
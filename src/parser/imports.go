@@ -0,0 +1,292 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"pogo/src/ast"
+	"pogo/src/lexer"
+	"pogo/src/linker"
+	"pogo/src/shared"
+	"pogo/src/token"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// importContext is shared by the parser of a program and the parsers of all
+// the modules it imports, directly or not. Every module is compiled once and
+// linked into the program the first time it is imported.
+type importContext struct {
+	root     *Parser
+	external bool // modules are not linked, their functions stay external
+	readFile func(name string) ([]byte, error)
+	linked   map[string]*importedModule // every module compiled so far by path
+	loading  []string                   // the chain of modules being compiled
+}
+
+// importedModule is a compiled module and the functions it defines, with
+// their final start quads when the module is linked into the program.
+type importedModule struct {
+	unit      *linker.Unit
+	functions []shared.Function
+}
+
+func (p *Parser) importContext() *importContext {
+	if p.imports == nil {
+		readFile := p.ReadFile
+		if readFile == nil {
+			readFile = os.ReadFile
+		}
+		p.imports = &importContext{
+			root:     p,
+			external: p.SeparateImports,
+			readFile: readFile,
+			linked:   make(map[string]*importedModule),
+		}
+	}
+	return p.imports
+}
+
+func (p *Parser) genImports(imports []*ast.Import) error {
+	for _, imp := range imports {
+		p.pos = imp.Pos
+		if err := p.genImport(imp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Parser) genImport(imp *ast.Import) error {
+	path := filepath.Join(filepath.Dir(p.Filename), imp.Path)
+
+	ctx := p.importContext()
+	module, err := ctx.load(path, imp.Line)
+	if err != nil {
+		return err
+	}
+
+	// Linking already declared the functions in the program and moved the
+	// module globals, a module that imports another one only needs the
+	// signatures to check its calls and globals of its own to stand in
+	// for the ones of the module until it is linked
+	var variables map[string]shared.Variable
+	if p == ctx.root && !ctx.external {
+		variables = make(map[string]shared.Variable)
+		for name, symbol := range p.SymbolTable.GetScopes()[linker.ModuleScope(path)] {
+			variables[name] = symbol.(shared.Variable)
+		}
+	} else {
+		for _, function := range module.functions {
+			if err := p.SymbolTable.AddImportedFunction(function); err != nil {
+				return fmt.Errorf("line %d: %v", imp.Line, err)
+			}
+		}
+		for _, def := range module.unit.Structs {
+			def.Module = path
+			if err := p.SymbolTable.AddImportedStruct(def); err != nil {
+				return fmt.Errorf("line %d: %v", imp.Line, err)
+			}
+		}
+		if variables, err = p.referenceGlobals(path, module.unit); err != nil {
+			return fmt.Errorf("line %d: %v", imp.Line, err)
+		}
+	}
+
+	if err := p.SymbolTable.AddModule(module.unit.Module, path, variables); err != nil {
+		return fmt.Errorf("line %d: %v", imp.Line, err)
+	}
+	return nil
+}
+
+// referenceGlobals allocates a global for every global of the module at path
+// and records them as references to be replaced when linking. Constants are
+// copied, their value is already known.
+func (p *Parser) referenceGlobals(path string, unit *linker.Unit) (map[string]shared.Variable, error) {
+	names := make([]string, 0, len(unit.Variables))
+	for name := range unit.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	variables := make(map[string]shared.Variable, len(names))
+	for _, name := range names {
+		variable, err := p.referenceGlobal(path, name, unit.Variables[name], unit)
+		if err != nil {
+			return nil, err
+		}
+		variables[name] = variable
+	}
+	return variables, nil
+}
+
+// referenceGlobal gives variable, called name in the module at path, its
+// stand-in address, a struct gets one for each of its fields.
+func (p *Parser) referenceGlobal(path, name string, variable shared.Variable, unit *linker.Unit) (shared.Variable, error) {
+	var err error
+	switch {
+	case variable.Constant:
+		variable.Address, err = linker.CopyConstant(unit.Memory, p.CodeGenerator.MemoryManager, variable.Address)
+	case variable.Type == shared.TypeStruct:
+		fields := make([]shared.Variable, len(variable.Fields))
+		for i, field := range variable.Fields {
+			if fields[i], err = p.referenceGlobal(path, name+"."+field.Name, field, unit); err != nil {
+				return variable, err
+			}
+		}
+		variable.Fields = fields
+	default:
+		variable.Address, err = p.CodeGenerator.MemoryManager.AllocateGlobal(variable.Type)
+		p.references = append(p.references, linker.Reference{Module: path, Name: name, Address: variable.Address})
+	}
+	return variable, err
+}
+
+// load compiles the module at path and links it into the program, unless it
+// was linked before.
+func (ctx *importContext) load(path string, line int) (*importedModule, error) {
+	if module, exists := ctx.linked[path]; exists {
+		return module, nil
+	}
+
+	if slices.Contains(ctx.loading, path) {
+		return nil, fmt.Errorf("line %d: import cycle: %s -> %s", line, strings.Join(ctx.loading, " -> "), path)
+	}
+
+	src, err := ctx.readFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: cannot import '%s': %v", line, path, err)
+	}
+
+	ctx.loading = append(ctx.loading, path)
+	defer func() { ctx.loading = ctx.loading[:len(ctx.loading)-1] }()
+
+	module := NewParser(lexer.NewLexer(src))
+	module.Filename = path
+	module.imports = ctx
+	module.SymbolTable.Natives = ctx.root.SymbolTable.Natives
+
+	unit, err := module.ParseModule()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	if ctx.external {
+		functions := make([]shared.Function, len(unit.Functions))
+		for i, function := range unit.Functions {
+			function.Module = path
+			function.StartQuad = -1
+			functions[i] = function
+		}
+		ctx.linked[path] = &importedModule{unit: unit, functions: functions}
+		return ctx.linked[path], nil
+	}
+
+	functions, err := linker.Link(ctx.root.CodeGenerator, ctx.root.SymbolTable, unit)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %v", line, err)
+	}
+
+	ctx.linked[path] = &importedModule{unit: unit, functions: functions}
+	return ctx.linked[path], nil
+}
+
+// ParseModule parses a module, a file with functions and globals but no main
+// section, and returns it as a unit that can be linked into a program.
+func (p *Parser) ParseModule() (*linker.Unit, error) {
+	module, err := p.parseFile()
+	if err != nil {
+		return nil, err
+	}
+	if !module.Module {
+		return nil, fmt.Errorf("line %d: expected %s, got %s", module.Line,
+			token.TokMap.Id(token.TokMap.Type("kwdModule")), token.TokMap.Id(token.TokMap.Type("kwdProgram")))
+	}
+
+	p.tree = module
+	p.moduleName = module.Name.Name
+	if err := p.genProgram(module); err != nil {
+		return nil, err
+	}
+	return p.Unit(), nil
+}
+
+// Parse parses a program or a module, whichever the file is, and reports
+// whether it was a module.
+func (p *Parser) Parse() (isModule bool, err error) {
+	if p.IsModule() {
+		_, err := p.ParseModule()
+		return true, err
+	}
+	return false, p.ParseProgram()
+}
+
+// IsModule reports whether the parsed file starts with a module header.
+func (p *Parser) IsModule() bool {
+	return p.curr.Type == token.TokMap.Type("kwdModule")
+}
+
+// Unit collects what the parsed file defines, the functions it imported are
+// recorded as externals and the globals of modules it uses as references.
+func (p *Parser) Unit() *linker.Unit {
+	unit := &linker.Unit{
+		Name:       p.Filename,
+		Module:     p.moduleName,
+		Quads:      p.CodeGenerator.Quads,
+		Functions:  make([]shared.Function, 0),
+		Externals:  make([]shared.Function, 0),
+		Structs:    make([]shared.StructDef, 0),
+		References: make([]linker.Reference, 0),
+		Variables:  make(map[string]shared.Variable),
+		Scopes:     make(map[string]map[string]shared.Variable),
+		Memory:     p.CodeGenerator.MemoryManager,
+	}
+
+	for scope, symbols := range p.SymbolTable.GetScopes() {
+		for name, symbol := range symbols {
+			switch v := symbol.(type) {
+			case shared.Function:
+				if v.Module == "" {
+					unit.Functions = append(unit.Functions, v)
+				} else {
+					unit.Externals = append(unit.Externals, v)
+				}
+			case shared.StructDef:
+				if v.Module == "" {
+					unit.Structs = append(unit.Structs, v)
+				}
+			case shared.Variable:
+				if scope == "global" {
+					unit.Variables[name] = v
+					continue
+				}
+				if unit.Scopes[scope] == nil {
+					unit.Scopes[scope] = make(map[string]shared.Variable)
+				}
+				unit.Scopes[scope][name] = v
+			}
+		}
+	}
+
+	// Only the globals of other modules the code uses have to be resolved
+	used := make(map[int]bool)
+	for _, quad := range unit.Quads {
+		for _, addr := range append(quad.ReadAddresses(), quad.ReferencedAddresses()...) {
+			used[addr] = true
+		}
+		if addr, ok := quad.WrittenAddress(); ok {
+			used[addr] = true
+		}
+	}
+	for _, reference := range p.references {
+		if used[reference.Address] {
+			unit.References = append(unit.References, reference)
+		}
+	}
+
+	sort.Slice(unit.Functions, func(i, j int) bool { return unit.Functions[i].Name < unit.Functions[j].Name })
+	sort.Slice(unit.Externals, func(i, j int) bool { return unit.Externals[i].Name < unit.Externals[j].Name })
+	sort.Slice(unit.Structs, func(i, j int) bool { return unit.Structs[i].Name < unit.Structs[j].Name })
+	return unit
+}
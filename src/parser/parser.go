@@ -2,17 +2,37 @@ package parser
 
 import (
 	"fmt"
+	"pogo/src/ast"
 	"pogo/src/lexer"
+	"pogo/src/linker"
 	"pogo/src/semantic"
 	"pogo/src/shared"
 	"pogo/src/token"
+	"strings"
 )
 
+// Parser builds the syntax tree of a file and generates its code by walking
+// the tree, declaring its symbols as it goes.
 type Parser struct {
 	lexer         *lexer.Lexer
 	curr          *token.Token
 	SymbolTable   *semantic.SymbolTable
 	CodeGenerator *semantic.QuadrupleList
+
+	// Filename is the file being parsed, imports are resolved relative to it.
+	Filename string
+	// ReadFile reads imported modules, os.ReadFile when nil.
+	ReadFile func(name string) ([]byte, error)
+	// SeparateImports compiles the file as an object: imported modules are
+	// only read for their signatures and calls to them are resolved when
+	// the objects are linked.
+	SeparateImports bool
+	imports         *importContext
+	tree            *ast.Program
+	pos             ast.Pos            // the statement or declaration being generated
+	moduleName      string             // the name in the module header
+	references      []linker.Reference // globals of imported modules standing in for theirs
+	scopes          []ScopeSpan        // where every scope starts and ends in the source
 }
 
 func NewParser(l *lexer.Lexer) *Parser {
@@ -25,826 +45,585 @@ func NewParser(l *lexer.Lexer) *Parser {
 	return p
 }
 
-func (p *Parser) ParseProgram() error {
-	p.CodeGenerator.HandleProgramStart()
-
-	if err := p.parseProgramName(); err != nil {
-		return err
-	}
-
-	if err := p.parseVarDeclarationSection(false); err != nil {
-		return err
-	}
-
-	if err := p.parseFunctionListOpt(); err != nil {
-		return err
-	}
-
-	if err := p.parseMainSection(); err != nil {
-		return err
-	}
-	// p.SymbolTable.PrettyPrint()
-	//p.CodeGenerator.Print()
-	//p.CodeGenerator.PrintStacks()
+// Parse returns the syntax tree of a program or a module without checking
+// what its names refer to, for tools that only need the tree.
+func Parse(src []byte) (*ast.Program, error) {
+	return NewParser(lexer.NewLexer(src)).parseFile()
+}
 
-	return nil
+// Tree returns the syntax tree of the parsed file, nil until it is parsed or
+// when it has syntax errors.
+func (p *Parser) Tree() *ast.Program {
+	return p.tree
 }
 
-func (p *Parser) parseProgramName() error {
-	if err := p.expect(token.TokMap.Type("kwdProgram")); err != nil {
+func (p *Parser) ParseProgram() error {
+	program, err := p.parseFile()
+	if err != nil {
 		return err
 	}
-	if err := p.expect(token.TokMap.Type("id")); err != nil {
-		return err
+	if program.Module {
+		return fmt.Errorf("line %d: expected %s, got %s", program.Line,
+			token.TokMap.Id(token.TokMap.Type("kwdProgram")), token.TokMap.Id(token.TokMap.Type("kwdModule")))
 	}
-	return p.expect(token.TokMap.Type("terminator"))
+
+	p.tree = program
+	return p.genProgram(program)
 }
 
-func (p *Parser) parseVarDeclarationSection(isFunction bool) error {
-	if p.curr.Type != token.TokMap.Type("kwdVars") {
-		if p.curr.Type != token.TokMap.Type("kwdFunc") && p.curr.Type != token.TokMap.Type("kwdBegin") && !isFunction {
-			return fmt.Errorf("line %d: unexpected token '%s', expected 'var', 'func', or 'begin'", p.curr.Line, p.curr.Lit)
-		}
-		return nil
-	}
+// parseFile parses a program or a module.
+func (p *Parser) parseFile() (*ast.Program, error) {
+	program := &ast.Program{Pos: p.position(), Module: p.IsModule()}
 
-	for p.curr.Type == token.TokMap.Type("kwdVars") {
-		if err := p.parseVarDeclaration(isFunction); err != nil {
-			return err
-		}
+	header := token.TokMap.Type("kwdProgram")
+	if program.Module {
+		header = token.TokMap.Type("kwdModule")
 	}
-
-	return nil
-}
-
-func (p *Parser) parseVarDeclaration(isFunction bool) error {
-	if err := p.expect(token.TokMap.Type("kwdVars")); err != nil {
-		return err
+	if err := p.expect(header); err != nil {
+		return nil, err
 	}
-
-	if err := p.parseVarList(); err != nil {
-		return err
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
 	}
-
-	// process varType in a later process
-
+	program.Name = name
 	if err := p.expect(token.TokMap.Type("terminator")); err != nil {
-		return err
+		return nil, err
 	}
 
-	return p.parseVarDeclarationSection(isFunction)
-}
-
-func (p *Parser) parseVarList() error {
-	currentVars := make([]string, 0)
-	initialVar := string(p.curr.Lit)
-	currentVars = append(currentVars, initialVar)
-	if err := p.expect(token.TokMap.Type("id")); err != nil {
-		return err
+	if program.Imports, err = p.parseImportList(); err != nil {
+		return nil, err
 	}
-	// Append id into currentVars
 
-	for p.curr.Type == token.TokMap.Type("repeatTerminator") {
-		p.next()
-		currentVars = append(currentVars, string(p.curr.Lit))
-		if err := p.expect(token.TokMap.Type("id")); err != nil {
-			return err
+	if !program.Module || p.curr.Type != token.EOF {
+		if program.Decls, err = p.parseVarDeclarationSection(); err != nil {
+			return nil, err
 		}
 	}
 
-	if err := p.expect(token.TokMap.Type("typeAssignOp")); err != nil {
-		return err
+	if program.Funcs, err = p.parseFunctionList(); err != nil {
+		return nil, err
 	}
 
-	currType := string(p.curr.Lit)
-	semType, err := p.returnSemanticType(currType)
-	if err != nil {
-		return err
+	if program.Module {
+		if p.curr.Type != token.EOF {
+			return nil, fmt.Errorf("line %d: unexpected token '%s', a module can only declare variables, constants and functions",
+				p.curr.Line, p.curr.Lit)
+		}
+		return program, nil
 	}
 
-	if err := p.addVariablesToSymbolTable(semType, currentVars); err != nil {
-		return err
+	if program.Main, err = p.parseMainSection(); err != nil {
+		return nil, err
 	}
+	return program, nil
+}
 
-	_, err = p.parseType()
-
-	if err != nil {
-		return err
-	}
+func (p *Parser) parseImportList() ([]*ast.Import, error) {
+	imports := make([]*ast.Import, 0)
+	for p.curr.Type == token.TokMap.Type("kwdImport") {
+		p.next()
 
-	return nil
-}
+		pathTok := p.curr
+		if err := p.expect(token.TokMap.Type("stringLit")); err != nil {
+			return nil, err
+		}
+		if err := p.expect(token.TokMap.Type("terminator")); err != nil {
+			return nil, err
+		}
 
-func (p *Parser) parseType() (string, error) {
-	currType := p.curr.Lit
-	if err := p.expect(token.TokMap.Type("type")); err != nil {
-		return "", err
+		imports = append(imports, &ast.Import{
+			Pos:  positionOf(pathTok),
+			Path: strings.Trim(string(pathTok.Lit), "\"`"),
+		})
 	}
-	return string(currType), nil
+	return imports, nil
 }
 
-func (p *Parser) parseFunctionListOpt() error {
-	if p.curr.Type != token.TokMap.Type("kwdFunc") {
-		return nil
+func (p *Parser) parseVarDeclarationSection() ([]ast.Decl, error) {
+	if p.curr.Type != token.TokMap.Type("kwdVars") && p.curr.Type != token.TokMap.Type("kwdConst") &&
+		p.curr.Type != token.TokMap.Type("kwdType") {
+		if p.curr.Type != token.TokMap.Type("kwdFunc") && p.curr.Type != token.TokMap.Type("kwdBegin") {
+			return nil, fmt.Errorf("line %d: unexpected token '%s', expected 'var', 'const', 'type', 'func', or 'begin'", p.curr.Line, p.curr.Lit)
+		}
+		return nil, nil
 	}
 
-	if p.curr.Type != token.TokMap.Type("kwdFunc") {
-		if p.curr.Type != token.TokMap.Type("kwdBegin") {
-			return fmt.Errorf("line %d: unexpected token '%s', expected 'var', 'func', or 'begin'",
-				p.curr.Line, p.curr.Lit)
+	decls := make([]ast.Decl, 0)
+	for {
+		var decl ast.Decl
+		var err error
+		switch p.curr.Type {
+		case token.TokMap.Type("kwdVars"):
+			decl, err = p.parseVarDeclaration()
+		case token.TokMap.Type("kwdConst"):
+			decl, err = p.parseConstDeclaration()
+		case token.TokMap.Type("kwdType"):
+			decl, err = p.parseTypeDeclaration()
+		default:
+			return decls, nil
 		}
-		return nil
+		if err != nil {
+			return nil, err
+		}
+		decls = append(decls, decl)
 	}
+}
 
-	if err := p.parseFunctionList(); err != nil {
-		return err
+// parseVarDeclaration parses the comma separated groups of a declaration,
+// e.g. "var a, b : int, c : float = 2.5;".
+func (p *Parser) parseVarDeclaration() (*ast.VarDecl, error) {
+	decl := &ast.VarDecl{Pos: p.position()}
+	if err := p.expect(token.TokMap.Type("kwdVars")); err != nil {
+		return nil, err
 	}
 
-	return nil
-}
-
-func (p *Parser) parseFunctionList() error {
+	for {
+		group, err := p.parseVarGroup()
+		if err != nil {
+			return nil, err
+		}
+		decl.Groups = append(decl.Groups, group)
 
-	for p.curr.Type == token.TokMap.Type("kwdFunc") {
-		if err := p.parseFunction(); err != nil {
-			return err
+		if p.curr.Type != token.TokMap.Type("repeatTerminator") {
+			break
 		}
+		p.next()
 	}
 
-	return nil
+	return decl, p.expect(token.TokMap.Type("terminator"))
 }
 
-func (p *Parser) parseFunction() error {
-
-	if err := p.expect(token.TokMap.Type("kwdFunc")); err != nil {
-		return err
-	}
-
-	functionId := p.curr.Lit
+func (p *Parser) parseVarGroup() (*ast.VarGroup, error) {
+	group := &ast.VarGroup{Pos: p.position()}
 
-	if err := p.expect(token.TokMap.Type("id")); err != nil {
-		return err
+	names, err := p.parseIdentList()
+	if err != nil {
+		return nil, err
 	}
+	group.Names = names
 
-	if err := p.expect(token.TokMap.Type("openParan")); err != nil {
-		return err
+	if err := p.expect(token.TokMap.Type("typeAssignOp")); err != nil {
+		return nil, err
 	}
-
-	p.CodeGenerator.MemoryManager.PushNewFunctionSegment(true, 0, 0)
-
-	params, err := p.parseParameterList()
-	if err != nil {
-		return err
+	if group.Type, err = p.parseVarType(); err != nil {
+		return nil, err
 	}
 
-	if err := p.SymbolTable.AddFunction(string(functionId), params, p.curr.Line, p.curr.Column); err != nil {
-		return err
+	if p.curr.Type == token.TokMap.Type("assignOp") {
+		p.next()
+		if group.Value, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
 	}
+	return group, nil
+}
 
-	if err := p.SymbolTable.EnterFunctionScope(string(functionId)); err != nil {
-		return err
-	}
+// parseIdentList parses one or more comma separated identifiers.
+func (p *Parser) parseIdentList() ([]*ast.Ident, error) {
+	idents := make([]*ast.Ident, 0)
+	for {
+		ident, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		idents = append(idents, ident)
 
-	if err := p.expect(token.TokMap.Type("closeParan")); err != nil {
-		return err
+		if p.curr.Type != token.TokMap.Type("repeatTerminator") {
+			return idents, nil
+		}
+		p.next()
 	}
+}
 
-	functionStartQuad := len(p.CodeGenerator.Quads)
-	if err := p.SymbolTable.UpdateFunctionStartQuad(string(functionId), functionStartQuad); err != nil {
-		return err
+func (p *Parser) parseIdent() (*ast.Ident, error) {
+	tok := p.curr
+	if err := p.expect(token.TokMap.Type("id")); err != nil {
+		return nil, err
 	}
+	return &ast.Ident{Pos: positionOf(tok), Name: string(tok.Lit)}, nil
+}
 
-	if err := p.parseFunctionBlock(); err != nil {
-		return err
+// parseVarType parses the type of a variable, parameter or field, a basic
+// type or the name of a struct type.
+func (p *Parser) parseVarType() (*ast.TypeName, error) {
+	typeName := &ast.TypeName{Pos: p.position(), Name: string(p.curr.Lit)}
+	if p.curr.Type == token.TokMap.Type("id") {
+		p.next()
+		typeName.Struct = true
+		return typeName, nil
 	}
 
-	if err := p.CodeGenerator.HandleENDPROC(); err != nil {
-		return err
+	if _, err := p.parseType(); err != nil {
+		return nil, err
 	}
+	return typeName, nil
+}
 
-	if err := p.CodeGenerator.MemoryManager.PopFunctionSegment(); err != nil {
-		return err
+func (p *Parser) parseType() (string, error) {
+	currType := p.curr.Lit
+	if err := p.expect(token.TokMap.Type("type")); err != nil {
+		return "", err
 	}
+	return string(currType), nil
+}
 
-	if err := p.expect(token.TokMap.Type("terminator")); err != nil {
-		return err
+func (p *Parser) parseFunctionList() ([]*ast.FuncDecl, error) {
+	functions := make([]*ast.FuncDecl, 0)
+	for p.curr.Type == token.TokMap.Type("kwdFunc") {
+		function, err := p.parseFunction()
+		if err != nil {
+			return nil, err
+		}
+		functions = append(functions, function)
 	}
-
-	p.SymbolTable.ExitFunctionScope()
-	return p.parseFunctionList()
+	return functions, nil
 }
 
-func (p *Parser) parseParameterList() ([]shared.Variable, error) {
-	if p.curr.Type == token.TokMap.Type("closeParan") {
-		return []shared.Variable{}, nil
+func (p *Parser) parseFunction() (*ast.FuncDecl, error) {
+	function := &ast.FuncDecl{Pos: p.position()}
+	if err := p.expect(token.TokMap.Type("kwdFunc")); err != nil {
+		return nil, err
 	}
 
-	currentParams := make([]shared.Variable, 0)
-	currId := p.curr
-	currType, err := p.parseParameter()
+	name, err := p.parseIdent()
 	if err != nil {
-		return []shared.Variable{}, err
+		return nil, err
 	}
+	function.Name = name
 
-	semType, err := p.returnSemanticType(currType)
-	addr, err := p.CodeGenerator.MemoryManager.AllocateLocal(semType)
+	if err := p.expect(token.TokMap.Type("openParan")); err != nil {
+		return nil, err
+	}
+	if function.Params, err = p.parseParameterList(); err != nil {
+		return nil, err
+	}
+	if err := p.expect(token.TokMap.Type("closeParan")); err != nil {
+		return nil, err
+	}
 
-	if err != nil {
-		return []shared.Variable{}, err
+	if function.Body, err = p.parseBlock(); err != nil {
+		return nil, err
 	}
 
-	currentParams = append(currentParams, shared.Variable{
-		Name:    string(currId.Lit),
-		Type:    semType,
-		Line:    currId.Line,
-		Column:  currId.Column,
-		Address: addr,
-	})
+	function.End = p.position()
+	return function, p.expect(token.TokMap.Type("terminator"))
+}
 
-	if err != nil {
-		return []shared.Variable{}, err
+func (p *Parser) parseParameterList() ([]*ast.Param, error) {
+	params := make([]*ast.Param, 0)
+	if p.curr.Type == token.TokMap.Type("closeParan") {
+		return params, nil
 	}
 
-	for p.curr.Type == token.TokMap.Type("repeatTerminator") {
-		p.next() // consume the repeat terminator
-		currId := p.curr
-		currType, err := p.parseParameter()
-		semType, err := p.returnSemanticType(currType)
-
-		if err != nil {
-			return []shared.Variable{}, err
-		}
-		addr, err := p.CodeGenerator.MemoryManager.AllocateLocal(semType)
+	for {
+		param, err := p.parseParameter()
 		if err != nil {
-			return []shared.Variable{}, err
+			return nil, err
 		}
-		currentParams = append(currentParams, shared.Variable{
-			Name:    string(currId.Lit),
-			Type:    semType,
-			Line:    currId.Line,
-			Column:  currId.Column,
-			Address: addr,
-		})
+		params = append(params, param)
 
-		if err != nil {
-			return []shared.Variable{}, err
+		if p.curr.Type != token.TokMap.Type("repeatTerminator") {
+			return params, nil
 		}
+		p.next() // consume the repeat terminator
 	}
-
-	return currentParams, nil
 }
 
-func (p *Parser) parseParameter() (string, error) {
-	if err := p.expect(token.TokMap.Type("id")); err != nil {
-		return "", err
-	}
-
-	if err := p.expect(token.TokMap.Type("typeAssignOp")); err != nil {
-		return "", err
+// parseParameter parses a parameter, one declared with ref refers to the
+// variable passed to it.
+func (p *Parser) parseParameter() (*ast.Param, error) {
+	param := &ast.Param{Pos: p.position()}
+	if p.curr.Type == token.TokMap.Type("kwdRef") {
+		param.Ref = true
+		p.next()
 	}
 
-	currType, err := p.parseType()
+	name, err := p.parseIdent()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	param.Name = name
 
-	return currType, nil
-}
-
-func (p *Parser) parseBlock() error {
-	if err := p.expect(token.TokMap.Type("openBrace")); err != nil {
-		return err
+	if err := p.expect(token.TokMap.Type("typeAssignOp")); err != nil {
+		return nil, err
 	}
-
-	if err := p.parseStatementList(); err != nil {
-		return err
+	if param.Type, err = p.parseVarType(); err != nil {
+		return nil, err
 	}
-
-	return p.expect(token.TokMap.Type("closeBrace"))
+	return param, nil
 }
 
-func (p *Parser) parseFunctionBlock() error {
+func (p *Parser) parseBlock() (*ast.Block, error) {
+	block := &ast.Block{Pos: p.position()}
 	if err := p.expect(token.TokMap.Type("openBrace")); err != nil {
-		return err
-	}
-
-	if err := p.parseVarDeclarationSection(true); err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := p.parseStatementList(); err != nil {
-		return err
+	stmts, err := p.parseBlockBody()
+	if err != nil {
+		return nil, err
 	}
+	block.Stmts = stmts
 
-	return p.expect(token.TokMap.Type("closeBrace"))
+	block.End = p.position()
+	return block, p.expect(token.TokMap.Type("closeBrace"))
 }
 
-// Parsing Statements
-func (p *Parser) parseStatementList() error {
+// parseBlockBody parses the contents of a block, var and const declarations
+// may appear anywhere between its statements.
+func (p *Parser) parseBlockBody() ([]ast.Stmt, error) {
+	stmts := make([]ast.Stmt, 0)
 	for {
-		validStatementStart, err := p.isStatementStart()
-		if err != nil {
-			return err
-		}
-
-		if !validStatementStart {
-			break // No more statements to parse
+		var stmt ast.Stmt
+		var err error
+		switch {
+		case p.curr.Type == token.TokMap.Type("kwdVars"):
+			stmt, err = p.parseVarDeclaration()
+		case p.curr.Type == token.TokMap.Type("kwdConst"):
+			stmt, err = p.parseConstDeclaration()
+		case p.isStatementStart():
+			stmt, err = p.parseStatement()
+		default:
+			return stmts, nil
 		}
-
-		if err := p.parseStatement(); err != nil {
-			return err
+		if err != nil {
+			return nil, err
 		}
+		stmts = append(stmts, stmt)
 	}
-	return nil
 }
 
-func (p *Parser) parseStatement() error {
-	validStatementStart, err := p.isStatementStart()
-
-	if !validStatementStart {
-		return nil
-	}
-
-	if err != nil {
-		return err
+// Parsing Statements
+func (p *Parser) parseStatementList() ([]ast.Stmt, error) {
+	stmts := make([]ast.Stmt, 0)
+	for p.isStatementStart() {
+		stmt, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
 	}
+	return stmts, nil
+}
 
+func (p *Parser) parseStatement() (ast.Stmt, error) {
 	switch p.curr.Type {
 	case token.TokMap.Type("kwdIf"):
 		return p.parseIfStatement()
 	case token.TokMap.Type("kwdWhile"):
 		return p.parseWhileStatement()
+	case token.TokMap.Type("kwdSwitch"):
+		return p.parseSwitchStatement()
 	case token.TokMap.Type("kwdPrint"):
 		return p.parsePrintStatement()
-	case token.TokMap.Type("id"):
-		// Functionality of function calls to be added
-
-		idToken := p.curr
-		p.next()
-		nextToken := p.curr
-		if nextToken.Type == token.TokMap.Type("openParan") {
-			return p.parseFunctionCall(idToken)
-		} else if nextToken.Type == token.TokMap.Type("assignOp") {
-			if err := p.SymbolTable.ValidateVarAssignment(string(idToken.Lit), idToken.Line); err != nil {
-				return err
-			}
-			return p.parseAssignment(idToken, nextToken)
-		} else {
-			return fmt.Errorf("expected either = or (, got %v at line %d, column %d", token.TokMap.Id(nextToken.Type), nextToken.Line, nextToken.Column)
-		}
-	}
-	return nil
-}
-
-func (p *Parser) parseAssignment(id, nextToken *token.Token) error {
-	if nextToken.Type != token.TokMap.Type("assignOp") {
-		return fmt.Errorf("expected =, got %v at line %d, column %d", nextToken.Type, nextToken.Line, nextToken.Column)
-	}
-	p.next()
-
-	_, err := p.parseExpression()
-	currType, err := p.SymbolTable.GetType(string(id.Lit))
-	if err != nil {
-		return err
 	}
 
-	targetAddr, err := p.SymbolTable.GetVariableAddress(string(id.Lit))
+	pos := p.position()
+	name, err := p.parseName()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// fmt.Println("The expression type is ", exprType, "and the tok", string(id.Lit))
-	if err := p.CodeGenerator.HandleAssignment(targetAddr, currType); err != nil {
-		return err
-	}
-	if err := p.expect(token.TokMap.Type("terminator")); err != nil {
-		return err
+	nextToken := p.curr
+	switch nextToken.Type {
+	case token.TokMap.Type("openParan"):
+		call, err := p.parseCall(&ast.Ident{Pos: name.Pos, Name: name.Name})
+		if err != nil {
+			return nil, err
+		}
+		return &ast.CallStmt{Pos: pos, Call: call}, nil
+	case token.TokMap.Type("assignOp"):
+		return p.parseAssignment(name)
+	default:
+		return nil, fmt.Errorf("expected either = or (, got %v at line %d, column %d", token.TokMap.Id(nextToken.Type), nextToken.Line, nextToken.Column)
 	}
-
-	return nil
 }
 
-func (p *Parser) parseWhileStatement() error {
-	if err := p.expect(token.TokMap.Type("kwdWhile")); err != nil {
-		return err
-	}
-
-	startIndex := p.CodeGenerator.HandleWhileStart()
-
-	if err := p.expect(token.TokMap.Type("openParan")); err != nil {
-		return err
+func (p *Parser) parseAssignment(target *ast.Name) (*ast.AssignStmt, error) {
+	if err := p.expect(token.TokMap.Type("assignOp")); err != nil {
+		return nil, err
 	}
 
-	_, err := p.parseExpression()
+	value, err := p.parseExpression()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// QUADS
-	if err := p.CodeGenerator.HandleWhileCondition(); err != nil {
-		return err
-	}
+	return &ast.AssignStmt{Pos: target.Pos, Target: target, Value: value}, p.expect(token.TokMap.Type("terminator"))
+}
 
-	if err := p.expect(token.TokMap.Type("closeParan")); err != nil {
-		return err
+func (p *Parser) parseWhileStatement() (*ast.WhileStmt, error) {
+	stmt := &ast.WhileStmt{Pos: p.position()}
+	if err := p.expect(token.TokMap.Type("kwdWhile")); err != nil {
+		return nil, err
 	}
 
-	if err := p.parseBlock(); err != nil {
-		return err
+	cond, err := p.parseCondition()
+	if err != nil {
+		return nil, err
 	}
+	stmt.Cond = cond
 
-	if err := p.CodeGenerator.HandleWhileEnd(startIndex); err != nil {
-		return err
+	if stmt.Body, err = p.parseBlock(); err != nil {
+		return nil, err
 	}
-
-	return nil
+	return stmt, nil
 }
 
-func (p *Parser) parseIfStatement() error {
+// parseIfStatement parses an if with its else if and else branches.
+func (p *Parser) parseIfStatement() (*ast.IfStmt, error) {
+	stmt := &ast.IfStmt{Pos: p.position()}
 	if err := p.expect(token.TokMap.Type("kwdIf")); err != nil {
-		return err
-	}
-
-	if err := p.expect(token.TokMap.Type("openParan")); err != nil {
-		return err
+		return nil, err
 	}
 
-	_, err := p.parseExpression()
+	cond, err := p.parseCondition()
 	if err != nil {
-		return err
-	}
-
-	if err := p.expect(token.TokMap.Type("closeParan")); err != nil {
-		return err
-	}
-
-	// QUADS
-	if err := p.CodeGenerator.HandleIfStatement(); err != nil {
-		return err
-	}
-
-	if err := p.parseBlock(); err != nil {
-		return err
-	}
-
-	if p.curr.Type == token.TokMap.Type("kwdElse") {
-		p.next()
-		if err := p.CodeGenerator.HandleElse(); err != nil {
-			return err
-		}
-
-		if err := p.parseBlock(); err != nil {
-			return err
-		}
-
-		if err := p.CodeGenerator.HandleEndIf(); err != nil {
-			return err
-		}
-
-		return nil
+		return nil, err
 	}
+	stmt.Cond = cond
 
-	if err := p.CodeGenerator.HandleEndIf(); err != nil {
-		return err
+	if stmt.Then, err = p.parseBlock(); err != nil {
+		return nil, err
 	}
 
-	return nil
-}
-
-func (p *Parser) parsePrintStatement() error {
-	if err := p.expect(token.TokMap.Type("kwdPrint")); err != nil {
-		return err
+	if p.curr.Type != token.TokMap.Type("kwdElse") {
+		return stmt, nil
 	}
+	p.next()
 
-	if err := p.expect(token.TokMap.Type("openParan")); err != nil {
-		return err
+	if p.curr.Type == token.TokMap.Type("kwdIf") {
+		stmt.Else, err = p.parseIfStatement()
+	} else {
+		stmt.Else, err = p.parseBlock()
 	}
-
-	if err := p.parsePrintList(); err != nil {
-		return err
+	if err != nil {
+		return nil, err
 	}
-
-	return p.expect(token.TokMap.Type("closeParan"))
+	return stmt, nil
 }
 
-func (p *Parser) parseFunctionCall(id *token.Token) error {
-	functionName := string(id.Lit)
-
+// parseCondition parses the parenthesized expression of an if, a while or
+// a switch.
+func (p *Parser) parseCondition() (ast.Expr, error) {
 	if err := p.expect(token.TokMap.Type("openParan")); err != nil {
-		return err
-	}
-	if err := p.CodeGenerator.HandleERA(functionName); err != nil {
-		return err
-	}
-
-	arguments, err := p.parseArgumentList()
-	if err != nil {
-		return err
-	}
-
-	if err := p.expect(token.TokMap.Type("closeParan")); err != nil {
-		return err
-	}
-
-	if err := p.SymbolTable.ValidateFunctionCall(string(id.Lit), id.Line, arguments); err != nil {
-		return err
+		return nil, err
 	}
 
-	startQuad, err := p.SymbolTable.GetFunctionStartQuad(string(id.Lit))
-
+	cond, err := p.parseExpression()
 	if err != nil {
-		return err
-	}
-
-	if err := p.CodeGenerator.HandleGOSUB(string(id.Lit), startQuad); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return cond, p.expect(token.TokMap.Type("closeParan"))
 }
 
-func (p *Parser) parseArgumentList() ([]shared.Type, error) {
-	if p.curr.Type == token.TokMap.Type("closeParan") {
-		return []shared.Type{}, nil
+func (p *Parser) parseSwitchStatement() (*ast.SwitchStmt, error) {
+	stmt := &ast.SwitchStmt{Pos: p.position()}
+	if err := p.expect(token.TokMap.Type("kwdSwitch")); err != nil {
+		return nil, err
 	}
-	paramCount := 0
-	argumentTypes := make([]shared.Type, 0)
-	currType, err := p.parseExpression()
+
+	tag, err := p.parseCondition()
 	if err != nil {
-		return []shared.Type{}, err
+		return nil, err
 	}
+	stmt.Tag = tag
 
-	if !p.CodeGenerator.OperandStack.IsEmpty() {
-		arg := p.CodeGenerator.OperandStack.Pop()
-		p.CodeGenerator.TypeStack.Pop()
-		if err := p.CodeGenerator.HandleParam(arg, paramCount); err != nil {
-			return []shared.Type{}, err
-		}
-		paramCount++
+	if err := p.expect(token.TokMap.Type("openBrace")); err != nil {
+		return nil, err
 	}
 
-	argumentTypes = append(argumentTypes, currType)
-
-	for p.curr.Type == token.TokMap.Type("repeatTerminator") {
+	for p.curr.Type == token.TokMap.Type("kwdCase") {
+		clause := &ast.CaseClause{Pos: p.position()}
 		p.next()
-		argType, err := p.parseExpression()
-		argumentTypes = append(argumentTypes, argType)
-		if err != nil {
-			return []shared.Type{}, err
-		}
 
-		if !p.CodeGenerator.OperandStack.IsEmpty() {
-			arg := p.CodeGenerator.OperandStack.Pop()
-			p.CodeGenerator.TypeStack.Pop()
-			if err := p.CodeGenerator.HandleParam(arg, paramCount); err != nil {
-				return []shared.Type{}, err
+		for {
+			value, err := p.parseExpression()
+			if err != nil {
+				return nil, err
 			}
-			paramCount++
-		}
+			clause.Values = append(clause.Values, value)
 
-		//argumentTypes = append(argumentTypes, argType)
-	}
-	return argumentTypes, nil
-}
+			if p.curr.Type != token.TokMap.Type("repeatTerminator") {
+				break
+			}
+			p.next()
+		}
 
-func (p *Parser) parsePrintList() error {
-	printItems := make([]interface{}, 0)
-	item, err := p.parsePrintItem()
-	if err != nil {
-		return err
+		if clause.Body, err = p.parseCaseBody(); err != nil {
+			return nil, err
+		}
+		stmt.Cases = append(stmt.Cases, clause)
 	}
 
-	printItems = append(printItems, item)
-
-	for p.curr.Type == token.TokMap.Type("repeatTerminator") {
+	if p.curr.Type == token.TokMap.Type("kwdDefault") {
+		stmt.Default = &ast.CaseClause{Pos: p.position()}
 		p.next()
-		item, err := p.parsePrintItem()
-		if err != nil {
-			return err
+
+		if stmt.Default.Body, err = p.parseCaseBody(); err != nil {
+			return nil, err
 		}
-		printItems = append(printItems, item)
 	}
 
-	if err := p.CodeGenerator.HandlePrint(printItems); err != nil {
-		return err
-	}
-	return nil
+	return stmt, p.expect(token.TokMap.Type("closeBrace"))
 }
 
-func (p *Parser) parsePrintItem() (interface{}, error) {
-
-	if p.curr.Type == token.TokMap.Type("stringLit") {
-		stringToSend := string(p.curr.Lit)
-		p.next()
-		return stringToSend, nil
-	}
-
-	_, err := p.parseExpression()
-	if err != nil {
+func (p *Parser) parseCaseBody() ([]ast.Stmt, error) {
+	if err := p.expect(token.TokMap.Type("typeAssignOp")); err != nil {
 		return nil, err
 	}
-
-	if p.CodeGenerator.OperandStack.IsEmpty() {
-		return nil, fmt.Errorf("missing expression result for print statement")
-	}
-	result := p.CodeGenerator.OperandStack.Pop()
-	p.CodeGenerator.TypeStack.Pop()
-
-	//if err := p.CodeGenerator.HandlePrint(result); err != nil {
-	//	return err
-	//}
-	//
-	//if err != nil {
-	//	return err
-	//}
-
-	return result, nil
+	return p.parseStatementList()
 }
 
-func (p *Parser) parseExpression() (shared.Type, error) {
-	leftType, err := p.parseExp()
-	if err != nil {
-		return shared.TypeError, err
-	}
-
-	for p.curr.Type == token.TokMap.Type("relOp") {
-		operator := string(p.curr.Lit)
-		p.CodeGenerator.OperatorStack.Push(operator)
-		p.next()
-
-		_, err := p.parseExp()
-		if err != nil {
-			return shared.TypeError, err
-		}
-
-		if err := p.CodeGenerator.HandleOp(); err != nil {
-			return shared.TypeError, err
-		}
+func (p *Parser) parsePrintStatement() (*ast.PrintStmt, error) {
+	stmt := &ast.PrintStmt{Pos: p.position()}
+	if err := p.expect(token.TokMap.Type("kwdPrint")); err != nil {
+		return nil, err
 	}
 
-	return leftType, nil
-}
-
-func (p *Parser) parseExp() (shared.Type, error) {
-	leftType, err := p.parseTerm()
-	if err != nil {
-		return shared.TypeError, err
+	if err := p.expect(token.TokMap.Type("openParan")); err != nil {
+		return nil, err
 	}
 
-	for p.curr.Type == token.TokMap.Type("expressionOp") {
-		operator := string(p.curr.Lit)
-		p.next()
-
-		p.CodeGenerator.OperatorStack.Push(operator)
-
-		rightType, err := p.parseTerm()
+	for {
+		item, err := p.parsePrintItem()
 		if err != nil {
-			return shared.TypeError, err
+			return nil, err
 		}
+		stmt.Items = append(stmt.Items, item)
 
-		if err := p.CodeGenerator.HandleOp(); err != nil {
-			return shared.TypeError, err
+		if p.curr.Type != token.TokMap.Type("repeatTerminator") {
+			break
 		}
-
-		if leftType == shared.TypeFloat || rightType == shared.TypeFloat {
-			leftType = shared.TypeFloat
-		} else {
-			leftType = shared.TypeInt
-		}
-
-	}
-
-	return leftType, nil
-}
-
-func (p *Parser) parseTerm() (shared.Type, error) {
-	leftType, err := p.parseFactor()
-	if err != nil {
-		return shared.TypeError, err
-	}
-
-	if p.curr.Type == token.TokMap.Type("termOp") {
-		operator := string(p.curr.Lit)
 		p.next()
-
-		p.CodeGenerator.OperatorStack.Push(operator)
-
-		rightType, err := p.parseTerm()
-
-		if err := p.CodeGenerator.HandleOp(); err != nil {
-			return shared.TypeError, err
-		}
-
-		if err != nil {
-			return shared.TypeError, err
-		}
-
-		// Check compatibility using semantic cube
-		if leftType == shared.TypeFloat || rightType == shared.TypeFloat {
-			return shared.TypeFloat, nil
-		}
-
-		return shared.TypeInt, nil
 	}
 
-	return leftType, nil
+	return stmt, p.expect(token.TokMap.Type("closeParan"))
 }
 
-func (p *Parser) parseFactor() (shared.Type, error) {
-	switch p.curr.Type {
-	case token.TokMap.Type("openParan"):
-		p.CodeGenerator.HandleOpenParen()
-		p.next()
-		exprType, err := p.parseExpression()
-		if err != nil {
-			return shared.TypeError, err
-		}
-
-		if p.curr.Type == token.TokMap.Type("closeParan") {
-			if err := p.CodeGenerator.HandleCloseParen(); err != nil {
-				return exprType, err
-			}
-		}
-		if err := p.expect(token.TokMap.Type("closeParan")); err != nil {
-			return shared.TypeError, err
-		}
-		return exprType, nil
-	case token.TokMap.Type("expressionOp"):
-		isNegative := string(p.curr.Lit) == "-"
+func (p *Parser) parsePrintItem() (ast.Expr, error) {
+	if p.curr.Type == token.TokMap.Type("stringLit") {
+		item := &ast.BasicLit{Pos: p.position(), Type: shared.TypeString, Value: string(p.curr.Lit)}
 		p.next()
-
-		switch p.curr.Type {
-		// Logic for negatives??? Plus sign should be parsed but ignored.
-		// Pending logic for negative ids
-		case token.TokMap.Type("intLit"), token.TokMap.Type("floatLit"):
-			tok := p.curr
-			tokType, err := p.getType(p.curr)
-			if err != nil {
-				return shared.TypeError, err
-			}
-
-			value := string(tok.Lit)
-			if isNegative {
-				value = "-" + value
-			}
-
-			if err := p.CodeGenerator.HandleFactor(value, tokType, p.SymbolTable); err != nil {
-				return shared.TypeError, err
-			}
-
-			return tokType, nil
-		case token.TokMap.Type("id"):
-			tok := p.curr
-			tokType, err := p.getType(p.curr)
-			if err != nil {
-				return shared.TypeError, err
-			}
-			if err := p.CodeGenerator.HandleFactor(string(tok.Lit), tokType, p.SymbolTable); err != nil {
-				return shared.TypeError, err
-			}
-
-			if isNegative {
-				if err := p.CodeGenerator.HandleNegation(); err != nil {
-					return shared.TypeError, err
-				}
-			}
-
-			return tokType, nil
-		default:
-			return shared.TypeError, fmt.Errorf("expected number after %s", p.curr.Lit)
-		}
-	case token.TokMap.Type("id"), token.TokMap.Type("intLit"), token.TokMap.Type("floatLit"):
-		tok := p.curr
-		tokType, err := p.getType(tok)
-		// fmt.Printf("In Parser parseFactor: token=%v type=%v lit=%v\n", p.curr.Type, tokType, string(tok.Lit))
-		if err != nil {
-			return shared.TypeError, err
-		}
-		if err := p.CodeGenerator.HandleFactor(string(tok.Lit), tokType, p.SymbolTable); err != nil {
-			return shared.TypeError, err
-		}
-		return tokType, nil
-	default:
-		return shared.TypeError, fmt.Errorf("unexpected token in factor: %v in line %v and %v",
-			token.TokMap.Id(p.curr.Type), p.curr.Line, string(p.curr.Lit))
+		return item, nil
 	}
+	return p.parseExpression()
 }
 
-func (p *Parser) parseMainSection() error {
+func (p *Parser) parseMainSection() (*ast.Block, error) {
+	main := &ast.Block{Pos: p.position()}
 	if err := p.expect(token.TokMap.Type("kwdBegin")); err != nil {
-		return err
-	}
-
-	mainQuadIndex := len(p.CodeGenerator.Quads)
-	p.CodeGenerator.Quads[0].Result = mainQuadIndex
-
-	if err := p.parseStatementList(); err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := p.expect(token.TokMap.Type("kwdEnd")); err != nil {
-		return err
+	stmts, err := p.parseBlockBody()
+	if err != nil {
+		return nil, err
 	}
+	main.Stmts = stmts
 
-	return nil
+	main.End = p.position()
+	return main, p.expect(token.TokMap.Type("kwdEnd"))
 }
@@ -0,0 +1,43 @@
+package parser
+
+import "pogo/src/ast"
+
+// ScopeSpan is the part of the source covered by a scope of the symbol
+// table, from the token that opens it to the token that closes it. A scope
+// left open by an error has an EndLine of 0.
+type ScopeSpan struct {
+	Scope       string
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+}
+
+// Contains reports whether the position at line and column is inside the span.
+func (s ScopeSpan) Contains(line, column int) bool {
+	if line < s.StartLine || (line == s.StartLine && column < s.StartColumn) {
+		return false
+	}
+	if s.EndLine == 0 {
+		return true
+	}
+	return line < s.EndLine || (line == s.EndLine && column <= s.EndColumn)
+}
+
+// Scopes returns the spans of the function, block and main scopes generated
+// so far, outer scopes come before the scopes they contain.
+func (p *Parser) Scopes() []ScopeSpan {
+	return p.scopes
+}
+
+// openScope records that scope starts at pos and returns its index for closeScope.
+func (p *Parser) openScope(scope string, pos ast.Pos) int {
+	p.scopes = append(p.scopes, ScopeSpan{Scope: scope, StartLine: pos.Line, StartColumn: pos.Column})
+	return len(p.scopes) - 1
+}
+
+// closeScope records that the scope at index ends at pos.
+func (p *Parser) closeScope(index int, pos ast.Pos) {
+	p.scopes[index].EndLine = pos.Line
+	p.scopes[index].EndColumn = pos.Column
+}
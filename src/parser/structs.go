@@ -0,0 +1,155 @@
+package parser
+
+import (
+	"fmt"
+	"pogo/src/ast"
+	"pogo/src/shared"
+	"pogo/src/token"
+)
+
+// parseTypeDeclaration parses a struct type, e.g.
+// "type Point struct { x, y : float; }".
+func (p *Parser) parseTypeDeclaration() (*ast.TypeDecl, error) {
+	decl := &ast.TypeDecl{Pos: p.position()}
+	if err := p.expect(token.TokMap.Type("kwdType")); err != nil {
+		return nil, err
+	}
+
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	decl.Name = name
+
+	if err := p.expect(token.TokMap.Type("kwdStruct")); err != nil {
+		return nil, err
+	}
+	if err := p.expect(token.TokMap.Type("openBrace")); err != nil {
+		return nil, err
+	}
+
+	for p.curr.Type != token.TokMap.Type("closeBrace") {
+		field := &ast.Field{Pos: p.position()}
+		if field.Names, err = p.parseIdentList(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(token.TokMap.Type("typeAssignOp")); err != nil {
+			return nil, err
+		}
+		if field.Type, err = p.parseVarType(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(token.TokMap.Type("terminator")); err != nil {
+			return nil, err
+		}
+		decl.Fields = append(decl.Fields, field)
+	}
+
+	if len(decl.Fields) == 0 {
+		return nil, fmt.Errorf("line %d: struct '%s' has no fields", name.Line, name.Name)
+	}
+	if err := p.expect(token.TokMap.Type("closeBrace")); err != nil {
+		return nil, err
+	}
+	if p.curr.Type == token.TokMap.Type("terminator") {
+		p.next()
+	}
+	return decl, nil
+}
+
+// genTypeDeclaration declares a struct type. Fields are ints, floats or
+// values of struct types declared before.
+func (p *Parser) genTypeDeclaration(decl *ast.TypeDecl) error {
+	p.pos = decl.Pos
+
+	def := shared.StructDef{
+		Name:   decl.Name.Name,
+		Line:   decl.Name.Line,
+		Column: decl.Name.Column,
+	}
+	declared := make(map[string]bool)
+
+	for _, field := range decl.Fields {
+		semType, err := p.semanticType(field.Type)
+		if err != nil {
+			return err
+		}
+		structName := ""
+		if semType == shared.TypeStruct {
+			structName = field.Type.Name
+		}
+
+		for _, name := range field.Names {
+			if declared[name.Name] {
+				return fmt.Errorf("line %d: field '%s' already declared in struct '%s'", name.Line, name.Name, def.Name)
+			}
+			declared[name.Name] = true
+			def.Fields = append(def.Fields, shared.StructField{Name: name.Name, Type: semType, Struct: structName})
+		}
+	}
+
+	return p.SymbolTable.AddStruct(def)
+}
+
+// addStructVariablesToSymbolTable declares variables of a struct type, each
+// one gets a global or local slot for every field.
+func (p *Parser) addStructVariablesToSymbolTable(structName string, names []*ast.Ident) error {
+	allocate := p.CodeGenerator.MemoryManager.AllocateGlobal
+	if p.SymbolTable.InFunction() {
+		allocate = func(semType shared.Type) (int, error) {
+			addr, err := p.CodeGenerator.MemoryManager.AllocateLocal(semType)
+			if err != nil {
+				return -1, err
+			}
+			return addr, p.SymbolTable.IncrementFunctionVarCount(semType)
+		}
+	}
+
+	for _, name := range names {
+		fields, err := p.SymbolTable.LayoutStruct(structName, allocate)
+		if err != nil {
+			return err
+		}
+		if err := p.SymbolTable.AddStructVariable(name.Name, structName, name.Line, name.Column, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// genStructAssignment copies a struct value field by field, e.g. "a = b;".
+func (p *Parser) genStructAssignment(stmt *ast.AssignStmt) error {
+	target, err := p.SymbolTable.GetVariable(stmt.Target.Name)
+	if err != nil {
+		return errorAt(stmt.Target, err)
+	}
+
+	source, err := p.structValue(stmt.Value, target.Struct)
+	if err != nil {
+		return err
+	}
+
+	p.CodeGenerator.HandleStructCopy(target, source)
+	return nil
+}
+
+// genStructArgument passes a struct value to param, its fields fill the
+// parameter slots starting at first. It returns the number of slots used.
+func (p *Parser) genStructArgument(arg ast.Expr, param shared.Variable, first int) (int, error) {
+	value, err := p.structValue(arg, param.Struct)
+	if err != nil {
+		return 0, err
+	}
+	return p.CodeGenerator.HandleStructParam(value, first)
+}
+
+// structValue returns the variable or field of the struct type called
+// structName that expr names, struct values can't be computed by
+// expressions.
+func (p *Parser) structValue(expr ast.Expr, structName string) (shared.Variable, error) {
+	name, ok := expr.(*ast.Name)
+	if !ok {
+		return shared.Variable{}, fmt.Errorf("line %d: expected a value of type %s", expr.Position().Line, structName)
+	}
+	return p.SymbolTable.GetStructValue(name.Name, structName, name.Line)
+}
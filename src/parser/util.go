@@ -2,6 +2,8 @@ package parser
 
 import (
 	"fmt"
+	"pogo/src/ast"
+	"pogo/src/semantic"
 	"pogo/src/shared"
 	"pogo/src/token"
 )
@@ -11,8 +13,6 @@ func (p *Parser) next() {
 }
 
 func (p *Parser) expect(typ token.Type) error {
-	//fmt.Println("Processing token", string(p.curr.Lit), "as ", token.TokMap.Id(typ))
-	// fmt.Println("This is the context", p.lexer.s)
 	if p.curr.Type != typ {
 		return p.error(fmt.Sprintf("expected %v, got %v", token.TokMap.Id(typ), token.TokMap.Id(p.curr.Type)))
 	}
@@ -20,92 +20,134 @@ func (p *Parser) expect(typ token.Type) error {
 	return nil
 }
 
+// parseName parses an identifier followed by any number of field accesses,
+// like p.origin.x, or a global of an imported module qualified with the
+// module name like utils.counter.
+func (p *Parser) parseName() (*ast.Name, error) {
+	name := &ast.Name{Pos: p.position(), Name: string(p.curr.Lit)}
+	if err := p.expect(token.TokMap.Type("id")); err != nil {
+		return nil, err
+	}
+
+	for p.curr.Type == token.TokMap.Type("dot") {
+		p.next()
+		member := p.curr
+		if err := p.expect(token.TokMap.Type("id")); err != nil {
+			return nil, err
+		}
+		name.Name += "." + string(member.Lit)
+	}
+	return name, nil
+}
+
+// position returns where the current token starts.
+func (p *Parser) position() ast.Pos {
+	return positionOf(p.curr)
+}
+
+func positionOf(tok *token.Token) ast.Pos {
+	return ast.Pos{Line: tok.Line, Column: tok.Column}
+}
+
+// Line returns the line where compiling stopped when it failed: the
+// current token while parsing, the statement being generated once the file
+// is parsed.
+func (p *Parser) Line() int {
+	if p.tree != nil {
+		return p.pos.Line
+	}
+	return p.curr.Line
+}
+
 // Error helper function
 func (p *Parser) error(msg string) error {
 	return fmt.Errorf("line %d: %s", p.curr.Line, msg)
 }
 
-func (p *Parser) isStatementStart() (bool, error) {
-	statementStarts := map[token.Type]struct{}{
-		token.TokMap.Type("kwdWhile"): {},
-		token.TokMap.Type("kwdIf"):    {},
-		token.TokMap.Type("kwdPrint"): {},
-		token.TokMap.Type("id"):       {},
-	}
+// errorAt prefixes the error of a semantic check with the line of node.
+func errorAt(node ast.Node, err error) error {
+	return fmt.Errorf("line %d: %v", node.Position().Line, err)
+}
 
-	if _, exists := statementStarts[p.curr.Type]; exists {
-		return true, nil
-	}
-	return false, nil
+// markPosition attributes the quads generated since startQuad to node, it
+// is meant to be deferred at the start of a statement.
+func (p *Parser) markPosition(startQuad int, node ast.Node) {
+	pos := node.Position()
+	p.CodeGenerator.MarkPosition(startQuad, pos.Line, pos.Column)
 }
 
-func (p *Parser) addVariablesToSymbolTable(semType shared.Type, currentVars []string) error {
+func (p *Parser) isStatementStart() bool {
+	switch p.curr.Type {
+	case token.TokMap.Type("kwdWhile"), token.TokMap.Type("kwdIf"), token.TokMap.Type("kwdSwitch"),
+		token.TokMap.Type("kwdPrint"), token.TokMap.Type("id"):
+		return true
+	}
+	return false
+}
 
-	for _, varName := range currentVars {
+func (p *Parser) addVariablesToSymbolTable(semType shared.Type, names []*ast.Ident) ([]int, error) {
+	addresses := make([]int, 0, len(names))
 
+	for _, name := range names {
 		var addr int
 		var err error
 
-		if p.SymbolTable.GetScope() == "global" {
+		if !p.SymbolTable.InFunction() {
 			addr, err = p.CodeGenerator.MemoryManager.AllocateGlobal(semType)
 		} else {
 			addr, err = p.CodeGenerator.MemoryManager.AllocateLocal(semType)
 			if err != nil {
-				return err
-			}
-			if err := p.SymbolTable.IncrementFunctionVarCount(semType); err != nil {
-				return err
+				return nil, err
 			}
+			err = p.SymbolTable.IncrementFunctionVarCount(semType)
 		}
-
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		if err := p.SymbolTable.AddVariable(varName, semType, p.curr.Line, p.curr.Column, addr); err != nil {
-			return err
+		if err := p.SymbolTable.AddVariable(name.Name, semType, name.Line, name.Column, addr); err != nil {
+			return nil, err
 		}
+		addresses = append(addresses, addr)
 	}
 
-	return nil
+	return addresses, nil
 }
 
-func (p *Parser) returnSemanticType(currType string) (shared.Type, error) {
-	var semType shared.Type
-	switch string(currType) {
-	case "int":
-		semType = shared.TypeInt
-	case "float":
-		semType = shared.TypeFloat
-	default:
-		return shared.TypeError, fmt.Errorf("line %d: unsupported type: %s", p.curr.Line, string(currType))
+// isConversion reports whether a call is a cast or a rounding builtin. A
+// variable with the same name as a builtin hides it.
+func (p *Parser) isConversion(call *ast.CallExpr) bool {
+	if call.Func.Name == "int" || call.Func.Name == "float" {
+		return true
+	}
+	if !semantic.IsConversion(call.Func.Name) {
+		return false
 	}
-	return semType, nil
+
+	_, err := p.SymbolTable.GetType(call.Func.Name)
+	return err != nil
 }
 
-func (p *Parser) getType(tok *token.Token) (shared.Type, error) {
-	switch tok.Type {
-	case token.TokMap.Type("intLit"):
-		p.next()
-		return shared.TypeInt, nil
-	case token.TokMap.Type("floatLit"):
-		p.next()
-		return shared.TypeFloat, nil
-	case token.TokMap.Type("id"):
-		p.next()
-		return p.SymbolTable.GetType(string(tok.Lit))
-	default:
-		return shared.TypeError, fmt.Errorf("expected number after %s", p.curr.Lit)
-	}
+// isHostCall reports whether a call is to a builtin or a native function.
+func (p *Parser) isHostCall(call *ast.CallExpr) bool {
+	return p.SymbolTable.IsNative(call.Func.Name) || p.SymbolTable.IsBuiltin(call.Func.Name)
 }
 
-func (p *Parser) isAssignable(varType, exprType shared.Type) bool {
-	if varType == exprType {
-		return true
+// semanticType returns the type a type name stands for, the struct type it
+// names has to be declared.
+func (p *Parser) semanticType(typeName *ast.TypeName) (shared.Type, error) {
+	if typeName.Struct {
+		if _, err := p.SymbolTable.GetStruct(typeName.Name); err != nil {
+			return shared.TypeError, errorAt(typeName, err)
+		}
+		return shared.TypeStruct, nil
 	}
-	// Allow int -> float conversion
-	if varType == shared.TypeFloat && exprType == shared.TypeInt {
-		return true
+
+	switch typeName.Name {
+	case "int":
+		return shared.TypeInt, nil
+	case "float":
+		return shared.TypeFloat, nil
 	}
-	return false
+	return shared.TypeError, fmt.Errorf("line %d: unsupported type: %s", typeName.Line, typeName.Name)
 }
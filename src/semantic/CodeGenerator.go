@@ -4,17 +4,21 @@ import (
 	"fmt"
 	"pogo/src/shared"
 	"pogo/src/virtualmachine"
+	"strconv"
 )
 
 // Quadruple struct
 
 type QuadrupleList struct {
 	Quads         []shared.Quadruple
-	OperatorStack *shared.Stack
 	OperandStack  *shared.Stack
 	TypeStack     *shared.Stack
 	JumpStack     *shared.Stack
+	ExitStack     *shared.Stack
+	SwitchStack   *shared.Stack
 	TempCounter   int
+	MainJump      int
+	MainFrame     int // the quad creating the activation record of main
 	SemanticCube  *SemanticCube
 	MemoryManager *virtualmachine.MemoryManager
 }
@@ -22,10 +26,11 @@ type QuadrupleList struct {
 func NewQuadrupleList() *QuadrupleList {
 	return &QuadrupleList{
 		Quads:         make([]shared.Quadruple, 0),
-		OperatorStack: shared.NewStack(),
 		OperandStack:  shared.NewStack(),
 		TypeStack:     shared.NewStack(),
 		JumpStack:     shared.NewStack(),
+		ExitStack:     shared.NewStack(),
+		SwitchStack:   shared.NewStack(),
 		TempCounter:   0,
 		SemanticCube:  NewSemanticCube(),
 		MemoryManager: virtualmachine.NewMemoryManager(),
@@ -33,6 +38,8 @@ func NewQuadrupleList() *QuadrupleList {
 
 }
 
+// HandleProgramStart emits the jump over the function declarations, it
+// follows the initialization of the global variables.
 func (ql *QuadrupleList) HandleProgramStart() {
 	quad := shared.Quadruple{
 		Operator: "goto",
@@ -41,9 +48,33 @@ func (ql *QuadrupleList) HandleProgramStart() {
 		Result:   nil,
 	}
 
+	ql.MainJump = len(ql.Quads)
 	ql.Quads = append(ql.Quads, quad)
 }
 
+// HandleMainStart points the program start jump to the next quad.
+func (ql *QuadrupleList) HandleMainStart() {
+	ql.Quads[ql.MainJump].Result = len(ql.Quads)
+}
+
+// HandleMainFrame emits the creation of the activation record of the main
+// section, HandleMainEnd sets its size once main is parsed.
+func (ql *QuadrupleList) HandleMainFrame() {
+	ql.MainFrame = len(ql.Quads)
+	ql.Quads = append(ql.Quads, shared.Quadruple{
+		Operator: "frame",
+		LeftOp:   0,
+		RightOp:  0,
+		Result:   nil,
+	})
+}
+
+// HandleMainEnd sets the number of int and float locals of the main section.
+func (ql *QuadrupleList) HandleMainEnd(intCount, floatCount int) {
+	ql.Quads[ql.MainFrame].LeftOp = intCount
+	ql.Quads[ql.MainFrame].RightOp = floatCount
+}
+
 func (ql *QuadrupleList) NewTemp(tempType shared.Type) (int, error) {
 	addr, err := ql.MemoryManager.AllocateTemp(tempType)
 	if err != nil {
@@ -52,114 +83,60 @@ func (ql *QuadrupleList) NewTemp(tempType shared.Type) (int, error) {
 	return addr, nil
 }
 
-func (ql *QuadrupleList) HandleOpenParen() {
-	ql.OperatorStack.Push("(")
-}
-
-func (ql *QuadrupleList) HandleCloseParen() error {
-	for !ql.OperatorStack.IsEmpty() {
-		topOp := ql.OperatorStack.Top()
-		if topOp == nil {
-			return fmt.Errorf("mismatched parentheses: no opening parenthesis found")
-		}
-
-		if topOp.(string) == "(" {
-			ql.OperatorStack.Pop()
-			return nil
-		}
-
-		operator := ql.OperatorStack.Pop().(string)
-
-		if ql.OperandStack.Size() < 2 {
-			return fmt.Errorf("insufficient operands for operator %s", operator)
-		}
-
-		rightOp := ql.OperandStack.Pop()
-		rightType := ql.TypeStack.Pop().(shared.Type)
-		leftOp := ql.OperandStack.Pop()
-		leftType := ql.TypeStack.Pop().(shared.Type)
-
-		resultType := ql.SemanticCube.GetResultType(leftType, rightType, operator)
-		if resultType == shared.TypeError {
-			return fmt.Errorf("type mismatch for operation %v %s %v", leftType, operator, rightType)
-		}
-
-		result, err := ql.NewTemp(resultType)
-
-		if err != nil {
-			return err
-		}
-
-		quad := shared.Quadruple{
-			Operator: operator,
-			LeftOp:   leftOp,
-			RightOp:  rightOp,
-			Result:   result,
-		}
-		ql.Quads = append(ql.Quads, quad)
+// HandleLiteral pushes an int or float literal, written as in the source
+// with an optional sign.
+func (ql *QuadrupleList) HandleLiteral(value string, valueType shared.Type) error {
+	if _, err := strconv.Atoi(value); err != nil && valueType == shared.TypeInt {
+		return fmt.Errorf("int literal '%s' out of range", value)
+	}
 
-		ql.OperandStack.Push(result)
-		ql.TypeStack.Push(resultType)
+	addr, err := ql.MemoryManager.AllocateConstant(value)
+	if err != nil {
+		return fmt.Errorf("error allocating value %s: %v", value, err)
 	}
 
-	return fmt.Errorf("mismatched parentheses: no opening parenthesis found")
+	ql.HandleOperand(addr, valueType)
+	return nil
 }
 
-func (ql *QuadrupleList) HandleOp() error {
-	if ql.OperatorStack.Top() != nil {
-		right := ql.OperandStack.Pop()
-		rightType := ql.TypeStack.Pop().(shared.Type)
-		left := ql.OperandStack.Pop()
-		leftType := ql.TypeStack.Pop().(shared.Type)
-		op := ql.OperatorStack.Pop().(string)
-
-		resultType := ql.SemanticCube.GetResultType(leftType, rightType, op)
-		if resultType == shared.TypeError {
-			return fmt.Errorf("type mismatch for operation %v %s %v", leftType, op, rightType)
-		}
-
-		result, err := ql.MemoryManager.AllocateTemp(resultType)
-
-		if err != nil {
-			return err
-		}
-		ql.TempCounter++
-
-		ql.Quads = append(ql.Quads, shared.Quadruple{
-			Operator: op,
-			LeftOp:   left,
-			RightOp:  right,
-			Result:   result,
-		})
+// HandleOperand pushes the value at addr, a variable or a constant.
+func (ql *QuadrupleList) HandleOperand(addr int, valueType shared.Type) {
+	ql.OperandStack.Push(addr)
+	ql.TypeStack.Push(valueType)
+}
 
-		ql.OperandStack.Push(result)
-		ql.TypeStack.Push(resultType)
+// HandleBinaryOp applies op to the two operands on top of the stack, the
+// right one on top.
+func (ql *QuadrupleList) HandleBinaryOp(op string) error {
+	if ql.OperandStack.Size() < 2 {
+		return fmt.Errorf("insufficient operands for operator %s", op)
 	}
 
-	return nil
-}
+	right := ql.OperandStack.Pop()
+	rightType := ql.TypeStack.Pop().(shared.Type)
+	left := ql.OperandStack.Pop()
+	leftType := ql.TypeStack.Pop().(shared.Type)
 
-func (ql *QuadrupleList) HandleFactor(value string, valueType shared.Type, SymbolTable *SymbolTable) error {
-	var addr int
-	var err error
-
-	if isNumeric(value) {
-		addr, err = ql.MemoryManager.AllocateConstant(value)
-		// fmt.Println("Numeric", addr, err)
-	} else {
-		addr, err = SymbolTable.GetVariableAddress(value)
+	resultType := ql.SemanticCube.GetResultType(leftType, rightType, op)
+	if resultType == shared.TypeError {
+		return fmt.Errorf("type mismatch for operation %v %s %v", leftType, op, rightType)
 	}
 
+	result, err := ql.NewTemp(resultType)
 	if err != nil {
-		return fmt.Errorf("error allocating value: %v", err, value)
+		return err
 	}
+	ql.TempCounter++
 
-	ql.OperandStack.Push(addr)
-	ql.TypeStack.Push(valueType)
-	//fmt.Printf("After HandleFactor(%v): Operands=%v, Operators=%v\n",
-	//	value,
-	//	ql.OperandStack,
-	//	ql.OperatorStack)
+	ql.Quads = append(ql.Quads, shared.Quadruple{
+		Operator: op,
+		LeftOp:   left,
+		RightOp:  right,
+		Result:   result,
+	})
+
+	ql.OperandStack.Push(result)
+	ql.TypeStack.Push(resultType)
 	return nil
 }
 
@@ -192,6 +169,38 @@ func (ql *QuadrupleList) HandleNegation() error {
 	return nil
 }
 
+// HandleConversion applies a cast or conversion builtin to the operand on
+// top of the stack.
+func (ql *QuadrupleList) HandleConversion(operator string) error {
+	if ql.OperandStack.Top() == nil {
+		return fmt.Errorf("missing expression for %s", operator)
+	}
+
+	value := ql.OperandStack.Pop()
+	valueType := ql.TypeStack.Pop().(shared.Type)
+
+	resultType := ql.SemanticCube.GetConversionType(operator, valueType)
+	if resultType == shared.TypeError {
+		return fmt.Errorf("cannot apply %s to value of type %v", operator, valueType)
+	}
+
+	result, err := ql.NewTemp(resultType)
+	if err != nil {
+		return err
+	}
+
+	ql.Quads = append(ql.Quads, shared.Quadruple{
+		Operator: operator,
+		LeftOp:   value,
+		Result:   result,
+	})
+
+	ql.OperandStack.Push(result)
+	ql.TypeStack.Push(resultType)
+
+	return nil
+}
+
 func (ql *QuadrupleList) HandleAssignment(target int, targetType shared.Type) error {
 	if ql.OperandStack.Top() == nil {
 		return fmt.Errorf("missing expression for assignment")
@@ -216,6 +225,29 @@ func (ql *QuadrupleList) HandleAssignment(target int, targetType shared.Type) er
 	return nil
 }
 
+// HandleInitialization assigns the value of an initializer expression to
+// every variable declared with it.
+func (ql *QuadrupleList) HandleInitialization(targets []int, targetType shared.Type) error {
+	if ql.OperandStack.Top() == nil {
+		return fmt.Errorf("missing expression for initialization")
+	}
+
+	value := ql.OperandStack.Top()
+	valueType := ql.TypeStack.Top()
+
+	for _, target := range targets {
+		ql.OperandStack.Push(value)
+		ql.TypeStack.Push(valueType)
+		if err := ql.HandleAssignment(target, targetType); err != nil {
+			return err
+		}
+	}
+
+	ql.OperandStack.Pop()
+	ql.TypeStack.Pop()
+	return nil
+}
+
 func (ql *QuadrupleList) HandleWhileStart() int {
 	return len(ql.Quads)
 }
@@ -292,6 +324,12 @@ func (ql *QuadrupleList) HandleIfStatement() error {
 	return nil
 }
 
+// HandleIfChainStart opens the exit-jump list shared by every branch of an
+// if / else if / else chain.
+func (ql *QuadrupleList) HandleIfChainStart() {
+	ql.ExitStack.Push([]int{})
+}
+
 func (ql *QuadrupleList) HandleElse() error {
 
 	quad := shared.Quadruple{
@@ -304,14 +342,15 @@ func (ql *QuadrupleList) HandleElse() error {
 	gotoIndex := len(ql.Quads)
 	ql.Quads = append(ql.Quads, quad)
 
-	if ql.JumpStack.IsEmpty() {
+	if ql.JumpStack.IsEmpty() || ql.ExitStack.IsEmpty() {
 		return fmt.Errorf("mismatched if-else: no corresponding if statement found")
 	}
 	falseJumpIndex := ql.JumpStack.Pop().(int)
 
 	ql.Quads[falseJumpIndex].Result = len(ql.Quads)
 
-	ql.JumpStack.Push(gotoIndex)
+	exits := ql.ExitStack.Pop().([]int)
+	ql.ExitStack.Push(append(exits, gotoIndex))
 
 	return nil
 }
@@ -328,6 +367,236 @@ func (ql *QuadrupleList) HandleEndIf() error {
 	return nil
 }
 
+// HandleIfChainEnd points every exit jump of the current if chain to the
+// quad that follows it.
+func (ql *QuadrupleList) HandleIfChainEnd() error {
+	if ql.ExitStack.IsEmpty() {
+		return fmt.Errorf("mismatched if-else: no pending exit jumps found")
+	}
+
+	for _, exitIndex := range ql.ExitStack.Pop().([]int) {
+		ql.Quads[exitIndex].Result = len(ql.Quads)
+	}
+
+	return nil
+}
+
+// switchContext keeps track of the case bodies of a switch statement while
+// they are generated, the dispatch code is only emitted once every case is known.
+type switchContext struct {
+	selector     int
+	dispatchJump int
+	caseValues   []int
+	caseStarts   []int
+	seen         map[int]bool
+	defaultStart int
+	exits        []int
+}
+
+const (
+	// A jump table is only used once a switch has enough cases and they are
+	// packed closely enough for the table not to be mostly holes.
+	jumpTableMinCases  = 4
+	jumpTableMaxSpread = 2
+)
+
+// HandleSwitchStart consumes the selector expression and emits the jump to
+// the dispatch code that is generated after the case bodies.
+func (ql *QuadrupleList) HandleSwitchStart() error {
+	if ql.OperandStack.IsEmpty() {
+		return fmt.Errorf("missing selector for switch statement")
+	}
+
+	selector := ql.OperandStack.Pop().(int)
+	selectorType := ql.TypeStack.Pop()
+
+	if selectorType != shared.TypeInt {
+		return fmt.Errorf("switch selector must be of type int, got %v", selectorType)
+	}
+
+	ql.SwitchStack.Push(&switchContext{
+		selector:     selector,
+		dispatchJump: len(ql.Quads),
+		seen:         make(map[int]bool),
+		defaultStart: -1,
+	})
+
+	ql.Quads = append(ql.Quads, shared.Quadruple{
+		Operator: "goto",
+		LeftOp:   nil,
+		RightOp:  nil,
+		Result:   nil,
+	})
+
+	return nil
+}
+
+func (ql *QuadrupleList) currentSwitch() (*switchContext, error) {
+	if ql.SwitchStack.IsEmpty() {
+		return nil, fmt.Errorf("case outside of switch statement")
+	}
+	return ql.SwitchStack.Top().(*switchContext), nil
+}
+
+// HandleCase registers the values of a case label, its body starts at the next quad.
+func (ql *QuadrupleList) HandleCase(values []int) error {
+	ctx, err := ql.currentSwitch()
+	if err != nil {
+		return err
+	}
+
+	if ctx.defaultStart != -1 {
+		return fmt.Errorf("case after default in switch statement")
+	}
+
+	for _, value := range values {
+		if ctx.seen[value] {
+			return fmt.Errorf("duplicate case value %d in switch statement", value)
+		}
+		ctx.seen[value] = true
+		ctx.caseValues = append(ctx.caseValues, value)
+		ctx.caseStarts = append(ctx.caseStarts, len(ql.Quads))
+	}
+
+	return nil
+}
+
+// HandleDefault marks the start of the default body of the current switch.
+func (ql *QuadrupleList) HandleDefault() error {
+	ctx, err := ql.currentSwitch()
+	if err != nil {
+		return err
+	}
+
+	if ctx.defaultStart != -1 {
+		return fmt.Errorf("multiple default labels in switch statement")
+	}
+	ctx.defaultStart = len(ql.Quads)
+
+	return nil
+}
+
+// HandleCaseEnd closes a case (or default) body with a jump out of the switch.
+func (ql *QuadrupleList) HandleCaseEnd() error {
+	ctx, err := ql.currentSwitch()
+	if err != nil {
+		return err
+	}
+
+	ctx.exits = append(ctx.exits, len(ql.Quads))
+	ql.Quads = append(ql.Quads, shared.Quadruple{
+		Operator: "goto",
+		LeftOp:   nil,
+		RightOp:  nil,
+		Result:   nil,
+	})
+
+	return nil
+}
+
+// HandleSwitchEnd emits the dispatch code of the current switch, a jump table
+// when the case values are dense and a sequence of comparisons otherwise.
+func (ql *QuadrupleList) HandleSwitchEnd() error {
+	ctx, err := ql.currentSwitch()
+	if err != nil {
+		return err
+	}
+	ql.SwitchStack.Pop()
+
+	ql.Quads[ctx.dispatchJump].Result = len(ql.Quads)
+
+	if ctx.isDense() {
+		ql.emitJumpTable(ctx)
+	} else if err := ql.emitComparisons(ctx); err != nil {
+		return err
+	}
+
+	if ctx.defaultStart != -1 {
+		ql.Quads = append(ql.Quads, shared.Quadruple{
+			Operator: "goto",
+			LeftOp:   nil,
+			RightOp:  nil,
+			Result:   ctx.defaultStart,
+		})
+	}
+
+	for _, exitIndex := range ctx.exits {
+		ql.Quads[exitIndex].Result = len(ql.Quads)
+	}
+
+	return nil
+}
+
+func (ctx *switchContext) bounds() (int, int) {
+	low, high := ctx.caseValues[0], ctx.caseValues[0]
+	for _, value := range ctx.caseValues {
+		low = min(low, value)
+		high = max(high, value)
+	}
+	return low, high
+}
+
+func (ctx *switchContext) isDense() bool {
+	if len(ctx.caseValues) < jumpTableMinCases {
+		return false
+	}
+	low, high := ctx.bounds()
+	return high-low+1 <= len(ctx.caseValues)*jumpTableMaxSpread
+}
+
+// emitJumpTable emits a single jumptable quad, values without a case (and
+// values out of range) fall through to the quad that follows it.
+func (ql *QuadrupleList) emitJumpTable(ctx *switchContext) {
+	low, high := ctx.bounds()
+	fallthroughIndex := len(ql.Quads) + 1
+
+	targets := make([]int, high-low+1)
+	for i := range targets {
+		targets[i] = fallthroughIndex
+	}
+	for i, value := range ctx.caseValues {
+		targets[value-low] = ctx.caseStarts[i]
+	}
+
+	ql.Quads = append(ql.Quads, shared.Quadruple{
+		Operator: "jumptable",
+		LeftOp:   ctx.selector,
+		RightOp:  low,
+		Result:   targets,
+	})
+}
+
+// emitComparisons emits a "selector != value" test per case value, gotof
+// jumps into the case body when the selector matches.
+func (ql *QuadrupleList) emitComparisons(ctx *switchContext) error {
+	for i, value := range ctx.caseValues {
+		valueAddr, err := ql.MemoryManager.AllocateConstant(fmt.Sprint(value))
+		if err != nil {
+			return err
+		}
+
+		result, err := ql.NewTemp(shared.TypeInt)
+		if err != nil {
+			return err
+		}
+
+		ql.Quads = append(ql.Quads, shared.Quadruple{
+			Operator: "!=",
+			LeftOp:   ctx.selector,
+			RightOp:  valueAddr,
+			Result:   result,
+		})
+		ql.Quads = append(ql.Quads, shared.Quadruple{
+			Operator: "gotof",
+			LeftOp:   result,
+			RightOp:  nil,
+			Result:   ctx.caseStarts[i],
+		})
+	}
+
+	return nil
+}
+
 func (ql *QuadrupleList) HandlePrint(items []interface{}) error {
 	addresses := make([]int, len(items))
 
@@ -357,6 +626,18 @@ func (ql *QuadrupleList) HandlePrint(items []interface{}) error {
 	return nil
 }
 
+// MarkPosition attributes the quads emitted since from that have no source
+// position yet to line and column. Nested statements are marked first, so
+// their quads keep the position of the innermost statement.
+func (ql *QuadrupleList) MarkPosition(from, line, column int) {
+	for i := from; i < len(ql.Quads); i++ {
+		if ql.Quads[i].Line == 0 {
+			ql.Quads[i].Line = line
+			ql.Quads[i].Column = column
+		}
+	}
+}
+
 func (ql *QuadrupleList) Print() {
 	// fmt.Println("Generated Quadruples:")
 	for i, quad := range ql.Quads {
@@ -386,6 +667,48 @@ func (ql *QuadrupleList) HandleParam(value interface{}, paramNum int) error {
 	return nil
 }
 
+// HandleStructParam passes a struct value field by field, its fields fill
+// the parameter slots starting at first. It returns the number of slots used.
+func (ql *QuadrupleList) HandleStructParam(value shared.Variable, first int) (int, error) {
+	fields := shared.Scalars(value.Fields)
+	for i, field := range fields {
+		if err := ql.HandleParam(field.Address, first+i); err != nil {
+			return 0, err
+		}
+	}
+	return len(fields), nil
+}
+
+// HandleRefParam passes variable by reference, a struct passes each of its
+// fields in the parameter slots starting at first. It returns the number of
+// slots used.
+func (ql *QuadrupleList) HandleRefParam(variable shared.Variable, first int) int {
+	fields := shared.Scalars([]shared.Variable{variable})
+	for i, field := range fields {
+		ql.Quads = append(ql.Quads, shared.Quadruple{
+			Operator: "paramref",
+			LeftOp:   field.Address,
+			RightOp:  first + i,
+			Result:   nil,
+		})
+	}
+	return len(fields)
+}
+
+// HandleStructCopy assigns every field of source to the same field of target,
+// both values are of the same struct type.
+func (ql *QuadrupleList) HandleStructCopy(target, source shared.Variable) {
+	targetFields := shared.Scalars(target.Fields)
+	for i, field := range shared.Scalars(source.Fields) {
+		ql.Quads = append(ql.Quads, shared.Quadruple{
+			Operator: "=",
+			LeftOp:   field.Address,
+			RightOp:  nil,
+			Result:   targetFields[i].Address,
+		})
+	}
+}
+
 func (ql *QuadrupleList) HandleGOSUB(functionName string, startQuad int) error {
 	quad := shared.Quadruple{
 		Operator: "gosub",
@@ -397,6 +720,40 @@ func (ql *QuadrupleList) HandleGOSUB(functionName string, startQuad int) error {
 	return nil
 }
 
+// HandleBuiltinCall emits the call to a builtin, the arguments are the
+// addresses of the already evaluated expressions. Builtins that return a
+// value leave it on the operand stack.
+func (ql *QuadrupleList) HandleBuiltinCall(name string, args []int, result shared.Type) error {
+	return ql.handleHostCall("callbuiltin", name, args, result)
+}
+
+// HandleNativeCall emits the call to a host function, like HandleBuiltinCall.
+func (ql *QuadrupleList) HandleNativeCall(name string, args []int, result shared.Type) error {
+	return ql.handleHostCall("callnative", name, args, result)
+}
+
+func (ql *QuadrupleList) handleHostCall(operator, name string, args []int, result shared.Type) error {
+	quad := shared.Quadruple{
+		Operator: operator,
+		LeftOp:   name,
+		RightOp:  args,
+		Result:   nil,
+	}
+
+	if result != shared.TypeVoid {
+		addr, err := ql.NewTemp(result)
+		if err != nil {
+			return err
+		}
+		quad.Result = addr
+		ql.OperandStack.Push(addr)
+		ql.TypeStack.Push(result)
+	}
+
+	ql.Quads = append(ql.Quads, quad)
+	return nil
+}
+
 func (ql *QuadrupleList) HandleENDPROC() error {
 	quad := shared.Quadruple{
 		Operator: "endproc",
@@ -409,7 +766,6 @@ func (ql *QuadrupleList) HandleENDPROC() error {
 }
 
 func (ql *QuadrupleList) PrintStacks() {
-	fmt.Println("Stack Operators", ql.OperatorStack)
 	fmt.Println("Stack Operands", ql.OperandStack)
 	fmt.Println("Stack Types", ql.TypeStack)
 }
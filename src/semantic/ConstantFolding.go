@@ -0,0 +1,85 @@
+package semantic
+
+import (
+	"fmt"
+	"pogo/src/shared"
+	"strconv"
+	"strings"
+)
+
+// Constant is a value known at compile time, Value holds an int or a float64.
+type Constant struct {
+	Type  shared.Type
+	Value interface{}
+}
+
+func IntConstant(value int) Constant {
+	return Constant{Type: shared.TypeInt, Value: value}
+}
+
+func FloatConstant(value float64) Constant {
+	return Constant{Type: shared.TypeFloat, Value: value}
+}
+
+func (c Constant) asFloat() float64 {
+	if v, ok := c.Value.(int); ok {
+		return float64(v)
+	}
+	return c.Value.(float64)
+}
+
+// Literal returns the constant written the way AllocateConstant expects it,
+// floats always carry a decimal point so they are not stored as ints.
+func (c Constant) Literal() string {
+	if c.Type == shared.TypeInt {
+		return strconv.Itoa(c.Value.(int))
+	}
+
+	literal := strconv.FormatFloat(c.asFloat(), 'g', -1, 64)
+	if !strings.ContainsAny(literal, ".eEIN") {
+		literal += ".0"
+	}
+	return literal
+}
+
+// ConvertTo converts the constant to the type of a declaration, following
+// the assignment rules of the semantic cube.
+func (sc *SemanticCube) ConvertTo(c Constant, target shared.Type) (Constant, error) {
+	if sc.GetResultType(target, c.Type, "=") == shared.TypeError {
+		return Constant{}, fmt.Errorf("cannot assign value of type %v to constant of type %v", c.Type, target)
+	}
+
+	if target == shared.TypeFloat {
+		return FloatConstant(c.asFloat()), nil
+	}
+	return c, nil
+}
+
+// Fold evaluates left operator right at compile time, the result has the
+// type the semantic cube gives to the operation.
+func (sc *SemanticCube) Fold(left Constant, operator string, right Constant) (Constant, error) {
+	resultType := sc.GetResultType(left.Type, right.Type, operator)
+	if resultType == shared.TypeError {
+		return Constant{}, fmt.Errorf("type mismatch for operation %v %s %v", left.Type, operator, right.Type)
+	}
+
+	value, err := shared.Arithmetic(operator, left.Value, right.Value)
+	if err != nil {
+		return Constant{}, fmt.Errorf("%v in constant expression", err)
+	}
+	return Constant{Type: resultType, Value: value}, nil
+}
+
+// FoldConversion evaluates a cast or conversion builtin at compile time.
+func (sc *SemanticCube) FoldConversion(operator string, c Constant) (Constant, error) {
+	resultType := sc.GetConversionType(operator, c.Type)
+	if resultType == shared.TypeError {
+		return Constant{}, fmt.Errorf("cannot apply %s to value of type %v", operator, c.Type)
+	}
+
+	value, err := shared.Convert(operator, c.Value)
+	if err != nil {
+		return Constant{}, fmt.Errorf("%v in constant expression", err)
+	}
+	return Constant{Type: resultType, Value: value}, nil
+}
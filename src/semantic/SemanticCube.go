@@ -19,13 +19,10 @@ func NewSemanticCube() *SemanticCube {
 		}
 	}
 
-	arithOps := []string{"+", "-", "*", "/"}
+	// Int operations stay int, "/" truncates and "%" keeps the sign of the dividend
+	arithOps := []string{"+", "-", "*", "/", "%", "**"}
 	for _, op := range arithOps {
-		// Int operations
 		cube.cube[shared.TypeInt][shared.TypeInt][op] = shared.TypeInt
-		//if op == "/" {
-		//	cube.cube[shared.TypeInt][shared.TypeInt][op] = shared.TypeFloat
-		//}
 		cube.cube[shared.TypeInt][shared.TypeFloat][op] = shared.TypeFloat
 
 		cube.cube[shared.TypeFloat][shared.TypeInt][op] = shared.TypeFloat
@@ -64,6 +61,35 @@ func (sc *SemanticCube) GetResultType(t1, t2 shared.Type, operator string) share
 	return shared.TypeError
 }
 
+// conversions maps every cast or conversion builtin to the type it produces.
+var conversions = map[string]shared.Type{
+	"int":   shared.TypeInt,
+	"float": shared.TypeFloat,
+	"round": shared.TypeInt,
+	"floor": shared.TypeInt,
+	"ceil":  shared.TypeInt,
+}
+
+// IsConversion reports whether name is a cast or conversion builtin.
+func IsConversion(name string) bool {
+	_, exists := conversions[name]
+	return exists
+}
+
+// GetConversionType returns the type produced by applying a conversion to a
+// value of type t, only numbers can be converted.
+func (sc *SemanticCube) GetConversionType(operator string, t shared.Type) shared.Type {
+	if t != shared.TypeInt && t != shared.TypeFloat {
+		return shared.TypeError
+	}
+
+	if result, exists := conversions[operator]; exists {
+		return result
+	}
+
+	return shared.TypeError
+}
+
 func (sc *SemanticCube) ValidatePrintItem(t shared.Type) bool {
 	return t == shared.TypeInt || t == shared.TypeFloat || t == shared.TypeString
 }
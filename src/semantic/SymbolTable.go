@@ -2,18 +2,38 @@ package semantic
 
 import (
 	"fmt"
+	"pogo/src/builtins"
+	"pogo/src/native"
 	"pogo/src/shared"
+	"strings"
 )
 
+// module is an imported module, its globals are reached as name.variable.
+type module struct {
+	path      string
+	variables map[string]shared.Variable
+}
+
 type SymbolTable struct {
 	variables    map[string]map[string]interface{}
 	scopeStack   []string
 	currentScope string
+	blockCounter int
+	modules      map[string]module // imported modules by the name in their header
+
+	// the locals of the main section by type, they live in its own frame
+	mainIntCount   int
+	mainFloatCount int
+
+	// Natives are the host functions the program may call, they take
+	// precedence over builtins with the same name.
+	Natives *native.Registry
 }
 
 func NewSymbolTable() *SymbolTable {
 	st := &SymbolTable{
 		variables: make(map[string]map[string]interface{}),
+		modules:   make(map[string]module),
 	}
 
 	st.variables["global"] = make(map[string]interface{})
@@ -22,12 +42,36 @@ func NewSymbolTable() *SymbolTable {
 	return st
 }
 
-func (st *SymbolTable) GetType(name string) (shared.Type, error) {
-	if st.variables[st.currentScope] == nil {
-		st.variables[st.currentScope] = make(map[string]interface{})
+// lookup resolves a name walking the scope stack from the innermost scope
+// outwards, so inner declarations shadow outer ones. A qualified name like
+// p.x is a field of a struct value, or a global of an imported module like
+// utils.counter when no struct variable is called like the module.
+func (st *SymbolTable) lookup(name string) (interface{}, bool) {
+	if base, path, qualified := strings.Cut(name, "."); qualified {
+		if symbol, exists := st.lookup(base); exists {
+			if variable, ok := symbol.(shared.Variable); ok && variable.Type == shared.TypeStruct {
+				return variable.Field(path)
+			}
+		}
+
+		member, path, _ := strings.Cut(path, ".")
+		variable, exists := st.modules[base].variables[member]
+		if exists && path != "" {
+			return variable.Field(path)
+		}
+		return variable, exists
 	}
 
-	if symbol, exists := st.variables[st.currentScope][name]; exists {
+	for i := len(st.scopeStack) - 1; i >= 0; i-- {
+		if symbol, exists := st.variables[st.scopeStack[i]][name]; exists {
+			return symbol, true
+		}
+	}
+	return nil, false
+}
+
+func (st *SymbolTable) GetType(name string) (shared.Type, error) {
+	if symbol, exists := st.lookup(name); exists {
 		switch v := symbol.(type) {
 		case shared.Variable:
 			return v.Type, nil
@@ -36,39 +80,28 @@ func (st *SymbolTable) GetType(name string) (shared.Type, error) {
 		}
 	}
 
-	if st.currentScope != "global" {
-		if symbol, exists := st.variables["global"][name]; exists {
-			switch v := symbol.(type) {
-			case shared.Variable:
-				return v.Type, nil
-			default:
-				return shared.TypeError, fmt.Errorf("symbol '%s' is not a variable", name)
-			}
+	return shared.TypeError, fmt.Errorf("variable '%s' not declared in accessible scope", name)
+}
+
+// GetVariable returns the variable called name visible from the current scope.
+func (st *SymbolTable) GetVariable(name string) (shared.Variable, error) {
+	if symbol, exists := st.lookup(name); exists {
+		if v, ok := symbol.(shared.Variable); ok {
+			return v, nil
 		}
+		return shared.Variable{}, fmt.Errorf("symbol '%s' is not a variable", name)
 	}
-
-	return shared.TypeError, fmt.Errorf("variable '%s' not declared in accessible scope", name)
+	return shared.Variable{}, fmt.Errorf("variable '%s' not declared in accessible scope", name)
 }
 
 func (st *SymbolTable) GetVariableAddress(name string) (int, error) {
-	// fmt.Println("This is the current scope: ", st.currentScope)
-
-	value, exists := st.variables[st.currentScope][name]
-	if exists {
+	if value, exists := st.lookup(name); exists {
 		if v, ok := value.(shared.Variable); ok {
 			return v.Address, nil
 		}
 	}
 
-	if st.currentScope != "global" {
-		if value, exists := st.variables["global"][name]; exists {
-			if v, ok := value.(shared.Variable); ok {
-				return v.Address, nil
-			}
-		}
-	}
-
-	return -1, fmt.Errorf("error retrieving address for '%v", name)
+	return -1, fmt.Errorf("error retrieving address for '%v'", name)
 }
 
 func (st *SymbolTable) AddVariable(name string, varType shared.Type, line, column int, addr int) error {
@@ -96,6 +129,33 @@ func (st *SymbolTable) AddVariable(name string, varType shared.Type, line, colum
 	return nil
 }
 
+// AddConstant declares a compile time constant whose value lives at addr in
+// the constant pool.
+func (st *SymbolTable) AddConstant(name string, constType shared.Type, line, column int, addr int) error {
+	if err := st.AddVariable(name, constType, line, column, addr); err != nil {
+		return err
+	}
+
+	constant := st.variables[st.currentScope][name].(shared.Variable)
+	constant.Constant = true
+	st.variables[st.currentScope][name] = constant
+	return nil
+}
+
+// GetConstant returns the constant called name visible from the current scope.
+func (st *SymbolTable) GetConstant(name string) (shared.Variable, error) {
+	symbol, exists := st.lookup(name)
+	if !exists {
+		return shared.Variable{}, fmt.Errorf("undefined constant '%s'", name)
+	}
+
+	constant, ok := symbol.(shared.Variable)
+	if !ok || !constant.Constant {
+		return shared.Variable{}, fmt.Errorf("'%s' is not a constant", name)
+	}
+	return constant, nil
+}
+
 func (st *SymbolTable) AddFunction(name string, params []shared.Variable, line, column int) error {
 	if _, exists := st.variables["global"][name]; exists {
 		return fmt.Errorf("line %d: symbol '%s' already declared", line, name)
@@ -108,8 +168,8 @@ func (st *SymbolTable) AddFunction(name string, params []shared.Variable, line,
 	intCount := 0
 	floatCount := 0
 
-	// Count parameters by type
-	for _, param := range params {
+	// Count parameters by type, a struct takes a slot per field
+	for _, param := range shared.Scalars(params) {
 		switch param.Type {
 		case shared.TypeInt:
 			intCount++
@@ -137,11 +197,24 @@ func (st *SymbolTable) AddFunction(name string, params []shared.Variable, line,
 }
 
 func (st *SymbolTable) IncrementFunctionVarCount(varType shared.Type) error {
-	if st.currentScope == "global" {
+	functionName, ok := st.functionScope()
+	if !ok {
 		return fmt.Errorf("cannot increment function variable count in global scope")
 	}
 
-	function, ok := st.variables["global"][st.currentScope].(shared.Function)
+	if functionName == MainScope {
+		switch varType {
+		case shared.TypeInt:
+			st.mainIntCount++
+		case shared.TypeFloat:
+			st.mainFloatCount++
+		default:
+			return fmt.Errorf("unsupported variable type for counting")
+		}
+		return nil
+	}
+
+	function, ok := st.variables["global"][functionName].(shared.Function)
 	if !ok {
 		return fmt.Errorf("current scope is not a function")
 	}
@@ -156,7 +229,7 @@ func (st *SymbolTable) IncrementFunctionVarCount(varType shared.Type) error {
 	}
 
 	// Update the function in global scope
-	st.variables["global"][st.currentScope] = function
+	st.variables["global"][functionName] = function
 	return nil
 }
 
@@ -169,6 +242,21 @@ func (st *SymbolTable) GetFunctionVarCounts(functionName string) (int, int, erro
 	return function.IntVarsCounter, function.FloatVarsCounter, nil
 }
 
+// GetMainVarCounts returns the number of int and float locals of the main section.
+func (st *SymbolTable) GetMainVarCounts() (int, int) {
+	return st.mainIntCount, st.mainFloatCount
+}
+
+// GetFunctionParameters returns the parameters of the function called name,
+// nil when there is no such function.
+func (st *SymbolTable) GetFunctionParameters(name string) []shared.Variable {
+	function, ok := st.variables["global"][name].(shared.Function)
+	if !ok {
+		return nil
+	}
+	return function.Parameters
+}
+
 func (st *SymbolTable) UpdateFunctionStartQuad(functionName string, start int) error {
 	function, ok := st.variables["global"][functionName].(shared.Function)
 	if !ok {
@@ -214,33 +302,38 @@ func (st *SymbolTable) GetFunctionStartQuad(functionName string) (int, error) {
 //}
 
 func (st *SymbolTable) ValidateVarAssignment(varName string, line int) error {
-	if st.variables[st.currentScope] == nil {
-		st.variables[st.currentScope] = make(map[string]interface{})
-	}
-
-	if symbol, exists := st.variables[st.currentScope][varName]; exists {
-		if _, ok := symbol.(shared.Variable); !ok {
+	if symbol, exists := st.lookup(varName); exists {
+		variable, ok := symbol.(shared.Variable)
+		if !ok {
 			return fmt.Errorf("line %d: '%s' is not a variable", line, varName)
 		}
-		return nil
-	}
-
-	// If not in current scope, check global
-	if st.currentScope != "global" {
-		if symbol, exists := st.variables["global"][varName]; exists {
-			if _, ok := symbol.(shared.Variable); !ok {
-				return fmt.Errorf("line %d: '%s' is not a variable", line, varName)
-			}
-			return nil
+		if variable.Constant {
+			return fmt.Errorf("line %d: cannot assign to constant '%s'", line, varName)
 		}
+		return nil
 	}
 
 	return fmt.Errorf("line %d: undefined variable '%s'", line, varName)
 }
 
-func (st *SymbolTable) ValidateFunctionCall(funcName string, line int, args []shared.Type) error {
+// ValidateFunctionCall checks the arguments of a call to funcName. The
+// argument of a parameter passed by reference must be a variable of exactly
+// the type of the parameter.
+func (st *SymbolTable) ValidateFunctionCall(funcName string, line int, args []shared.Argument) error {
 	symbol, exists := st.variables["global"][funcName]
 	if !exists {
+		argTypes := make([]shared.Type, len(args))
+		for i, arg := range args {
+			argTypes[i] = arg.Type
+		}
+		if st.IsNative(funcName) {
+			_, err := st.ValidateNativeCall(funcName, line, argTypes)
+			return err
+		}
+		if st.IsBuiltin(funcName) {
+			_, err := st.ValidateBuiltinCall(funcName, line, argTypes)
+			return err
+		}
 		return fmt.Errorf("line %d: undefined function '%s'", line, funcName)
 	}
 
@@ -250,14 +343,25 @@ func (st *SymbolTable) ValidateFunctionCall(funcName string, line int, args []sh
 	}
 
 	if len(function.Parameters) != len(args) {
-		fmt.Println(function.Parameters)
-		fmt.Println(args)
 		return fmt.Errorf("line %d: function '%s' expects %d arguments but got %d",
 			line, funcName, len(function.Parameters), len(args))
 	}
 
 	for i, paramVar := range function.Parameters {
-		argType := args[i]
+		argType := args[i].Type
+
+		if paramVar.Ref {
+			variable := args[i].Variable
+			if variable == nil || variable.Constant {
+				return fmt.Errorf("line %d: argument for parameter '%s' in function '%s' must be a variable, it is passed by reference",
+					line, paramVar.Name, funcName)
+			}
+			if argType != paramVar.Type || variable.Struct != paramVar.Struct {
+				return fmt.Errorf("line %d: invalid argument type for reference parameter '%s' in function '%s': expected %s, got %s",
+					line, paramVar.Name, funcName, TypeName(paramVar), TypeName(*variable))
+			}
+			continue
+		}
 
 		// Direct type match
 		if paramVar.Type == argType {
@@ -276,9 +380,289 @@ func (st *SymbolTable) ValidateFunctionCall(funcName string, line int, args []sh
 	return nil
 }
 
+// TypeName is the type of a variable as written in the program, the name of
+// its struct type for a struct.
+func TypeName(variable shared.Variable) string {
+	if variable.Type == shared.TypeStruct {
+		return variable.Struct
+	}
+	return variable.Type.String()
+}
+
+// IsBuiltin reports whether name refers to a registered builtin, symbols
+// declared in the program with the same name hide it.
+func (st *SymbolTable) IsBuiltin(name string) bool {
+	if _, exists := st.lookup(name); exists {
+		return false
+	}
+	_, exists := builtins.Lookup(name)
+	return exists
+}
+
+// IsNative reports whether name refers to a host function that is not
+// hidden by a symbol of the program.
+func (st *SymbolTable) IsNative(name string) bool {
+	if _, exists := st.lookup(name); exists {
+		return false
+	}
+	_, exists := st.Natives.Lookup(name)
+	return exists
+}
+
+// ValidateNativeCall checks the arguments of a call to a host function and
+// returns its signature.
+func (st *SymbolTable) ValidateNativeCall(name string, line int, args []shared.Type) (native.Signature, error) {
+	function, exists := st.Natives.Lookup(name)
+	if !exists {
+		return native.Signature{}, fmt.Errorf("line %d: undefined function '%s'", line, name)
+	}
+
+	if !function.Signature.Accepts(args) {
+		return native.Signature{}, fmt.Errorf("line %d: native function '%s' expects arguments %v but got %v",
+			line, name, function.Signature.Parameters, args)
+	}
+	return function.Signature, nil
+}
+
+// ValidateBuiltinCall checks the arguments of a builtin call against the
+// registered signatures and returns the one that matched.
+func (st *SymbolTable) ValidateBuiltinCall(name string, line int, args []shared.Type) (builtins.Signature, error) {
+	builtin, exists := builtins.Lookup(name)
+	if !exists {
+		return builtins.Signature{}, fmt.Errorf("line %d: undefined function '%s'", line, name)
+	}
+
+	signature, ok := builtin.Resolve(args)
+	if !ok {
+		return builtins.Signature{}, fmt.Errorf("line %d: no signature of builtin '%s' accepts arguments %v", line, name, args)
+	}
+	return signature, nil
+}
+
+// AddImportedFunction declares a function defined by an imported module in
+// the global scope. Importing the same function again is not an error.
+func (st *SymbolTable) AddImportedFunction(function shared.Function) error {
+	if symbol, exists := st.variables["global"][function.Name]; exists {
+		if existing, ok := symbol.(shared.Function); ok && existing.Module == function.Module {
+			return nil
+		}
+		return fmt.Errorf("function '%s' imported from '%s' collides with a symbol already declared", function.Name, function.Module)
+	}
+
+	st.variables["global"][function.Name] = function
+	return nil
+}
+
+// AddStruct declares a struct type in the global scope.
+func (st *SymbolTable) AddStruct(def shared.StructDef) error {
+	if _, exists := st.variables["global"][def.Name]; exists {
+		return fmt.Errorf("line %d: symbol '%s' already declared", def.Line, def.Name)
+	}
+
+	st.variables["global"][def.Name] = def
+	return nil
+}
+
+// AddImportedStruct declares a struct type defined by an imported module,
+// importing the same type again is not an error.
+func (st *SymbolTable) AddImportedStruct(def shared.StructDef) error {
+	if symbol, exists := st.variables["global"][def.Name]; exists {
+		if existing, ok := symbol.(shared.StructDef); ok && existing.Module == def.Module {
+			return nil
+		}
+		return fmt.Errorf("struct '%s' imported from '%s' collides with a symbol already declared", def.Name, def.Module)
+	}
+
+	st.variables["global"][def.Name] = def
+	return nil
+}
+
+// GetStruct returns the struct type called name.
+func (st *SymbolTable) GetStruct(name string) (shared.StructDef, error) {
+	def, ok := st.variables["global"][name].(shared.StructDef)
+	if !ok {
+		return shared.StructDef{}, fmt.Errorf("undefined type '%s'", name)
+	}
+	return def, nil
+}
+
+// LayoutStruct gives every field of a value of the struct type called name
+// a slot taken with allocate, the fields of nested structs are laid out one
+// by one.
+func (st *SymbolTable) LayoutStruct(name string, allocate func(shared.Type) (int, error)) ([]shared.Variable, error) {
+	def, err := st.GetStruct(name)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]shared.Variable, len(def.Fields))
+	for i, field := range def.Fields {
+		fields[i] = shared.Variable{
+			Name:    field.Name,
+			Type:    field.Type,
+			Line:    def.Line,
+			Column:  def.Column,
+			Address: -1,
+			Struct:  field.Struct,
+		}
+
+		if field.Type == shared.TypeStruct {
+			fields[i].Fields, err = st.LayoutStruct(field.Struct, allocate)
+		} else {
+			fields[i].Address, err = allocate(field.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+// AddStructVariable declares a variable of a struct type whose fields were
+// laid out with LayoutStruct.
+func (st *SymbolTable) AddStructVariable(name, structName string, line, column int, fields []shared.Variable) error {
+	if st.variables[st.currentScope] == nil {
+		st.variables[st.currentScope] = make(map[string]interface{})
+	}
+
+	if _, exists := st.variables[st.currentScope][name]; exists {
+		return fmt.Errorf("line %d: symbol '%s' already declared in current scope", line, name)
+	}
+
+	st.variables[st.currentScope][name] = shared.Variable{
+		Name:    name,
+		Type:    shared.TypeStruct,
+		Line:    line,
+		Column:  column,
+		Address: -1,
+		Struct:  structName,
+		Fields:  fields,
+	}
+	return nil
+}
+
+// GetStructValue returns the struct variable or field called name, which
+// must be of the struct type called structName.
+func (st *SymbolTable) GetStructValue(name, structName string, line int) (shared.Variable, error) {
+	symbol, exists := st.lookup(name)
+	if !exists {
+		return shared.Variable{}, fmt.Errorf("line %d: undefined variable '%s'", line, name)
+	}
+
+	variable, ok := symbol.(shared.Variable)
+	if !ok || variable.Type != shared.TypeStruct {
+		return shared.Variable{}, fmt.Errorf("line %d: expected a value of type %s, got '%s'", line, structName, name)
+	}
+	if variable.Struct != structName {
+		return shared.Variable{}, fmt.Errorf("line %d: expected a value of type %s, '%s' is a %s", line, structName, name, variable.Struct)
+	}
+	return variable, nil
+}
+
+// AddModule makes the globals of the module imported from path visible as
+// name.variable. The same module may be imported more than once.
+func (st *SymbolTable) AddModule(name, path string, variables map[string]shared.Variable) error {
+	if existing, exists := st.modules[name]; exists && existing.path != path {
+		return fmt.Errorf("module '%s' imported from '%s' is already imported from '%s'", name, path, existing.path)
+	}
+
+	st.modules[name] = module{path: path, variables: variables}
+	return nil
+}
+
+// AddScope adds a scope that is not part of the scope stack, it is used to
+// keep the variables of imported modules so they can be named in messages.
+func (st *SymbolTable) AddScope(name string, symbols map[string]shared.Variable) {
+	scope := make(map[string]interface{}, len(symbols))
+	for symbolName, variable := range symbols {
+		scope[symbolName] = variable
+	}
+	st.variables[name] = scope
+}
+
+// GetFunctionInfos returns what the virtual machine needs to know about
+// every function of the program.
+func (st *SymbolTable) GetFunctionInfos() map[string]shared.FunctionInfo {
+	functions := make(map[string]shared.FunctionInfo)
+	for name, symbol := range st.variables["global"] {
+		if function, ok := symbol.(shared.Function); ok {
+			functions[name] = shared.FunctionInfo{
+				Name:           function.Name,
+				StartQuad:      function.StartQuad,
+				IntVarsCount:   function.IntVarsCounter,
+				FloatVarsCount: function.FloatVarsCounter,
+				Parameters:     shared.Scalars(function.Parameters),
+			}
+		}
+	}
+	return functions
+}
+
 func (st *SymbolTable) ExitFunctionScope() {
+	st.exitScope()
+}
+
+// EnterBlockScope opens an anonymous scope for the declarations of a block,
+// it is named after its parent scope so every block gets a unique entry in
+// the variables map.
+func (st *SymbolTable) EnterBlockScope() string {
+	st.blockCounter++
+	name := fmt.Sprintf("%s#%d", st.currentScope, st.blockCounter)
+
+	st.variables[name] = make(map[string]interface{})
+	st.scopeStack = append(st.scopeStack, name)
+	st.currentScope = name
+	return name
+}
+
+func (st *SymbolTable) ExitBlockScope() {
+	st.exitScope()
+}
+
+func (st *SymbolTable) exitScope() {
 	st.scopeStack = st.scopeStack[:len(st.scopeStack)-1]
-	st.currentScope = "global"
+	st.currentScope = st.scopeStack[len(st.scopeStack)-1]
+}
+
+// functionScope returns the name of the function enclosing the current
+// scope, MainScope inside the main section.
+func (st *SymbolTable) functionScope() (string, bool) {
+	if len(st.scopeStack) < 2 {
+		return "", false
+	}
+
+	if st.scopeStack[1] == MainScope {
+		return MainScope, true
+	}
+
+	if _, ok := st.variables["global"][st.scopeStack[1]].(shared.Function); !ok {
+		return "", false
+	}
+	return st.scopeStack[1], true
+}
+
+// InFunction reports whether declarations in the current scope belong to a
+// function activation record, or the one of the main section, rather than to
+// global memory.
+func (st *SymbolTable) InFunction() bool {
+	_, ok := st.functionScope()
+	return ok
+}
+
+// MainScope is the scope of the variables declared in the main section, it
+// is not a valid identifier so no function can be called like it.
+const MainScope = "<main>"
+
+// EnterMainScope opens the scope of the main section.
+func (st *SymbolTable) EnterMainScope() {
+	st.variables[MainScope] = make(map[string]interface{})
+	st.scopeStack = append(st.scopeStack, MainScope)
+	st.currentScope = MainScope
+}
+
+// ExitMainScope closes the scope of the main section.
+func (st *SymbolTable) ExitMainScope() {
+	st.exitScope()
 }
 
 func (st *SymbolTable) EnterFunctionScope(name string) error {
@@ -344,10 +728,10 @@ func (st *SymbolTable) PrettyPrint() {
 		fmt.Println()
 	}
 
-	// Print other scopes (function scopes)
+	// Print other scopes (function and block scopes)
 	for scope, symbols := range st.variables {
 		if scope != "global" {
-			fmt.Printf("\nFunction Scope: %s\n", scope)
+			fmt.Printf("\nScope: %s\n", scope)
 			fmt.Println("------------------")
 			for name, symbol := range symbols {
 				switch v := symbol.(type) {
@@ -367,3 +751,19 @@ func (st *SymbolTable) PrettyPrint() {
 func (st *SymbolTable) GetGlobalScope() map[string]interface{} {
 	return st.variables["global"]
 }
+
+// GetScopes returns every scope of the program keyed by scope name: "global",
+// one per function, MainScope and one per block, block scopes are named after
+// the scope that contains them ("fib#3").
+func (st *SymbolTable) GetScopes() map[string]map[string]interface{} {
+	return st.variables
+}
+
+// ScopeOwner returns the function a scope belongs to, MainScope for the main
+// section and its blocks or "global" for the global scope.
+func ScopeOwner(scope string) string {
+	if i := strings.Index(scope, "#"); i >= 0 {
+		return scope[:i]
+	}
+	return scope
+}
@@ -0,0 +1,169 @@
+package shared
+
+import (
+	"fmt"
+	"math"
+)
+
+// Arithmetic applies an arithmetic operator to two int or float64 values.
+// Two ints give an int, any float operand makes the operation a float one,
+// matching the result types of the semantic cube.
+func Arithmetic(operator string, left, right interface{}) (interface{}, error) {
+	l, leftIsInt := left.(int)
+	r, rightIsInt := right.(int)
+	if leftIsInt && rightIsInt {
+		return intArithmetic(operator, l, r)
+	}
+
+	lf, err := toFloat(left)
+	if err != nil {
+		return nil, fmt.Errorf("invalid left operand type: %T", left)
+	}
+	rf, err := toFloat(right)
+	if err != nil {
+		return nil, fmt.Errorf("invalid right operand type: %T", right)
+	}
+	return floatArithmetic(operator, lf, rf)
+}
+
+// intArithmetic reports an overflow instead of wrapping around when the
+// result does not fit in an int.
+func intArithmetic(operator string, l, r int) (interface{}, error) {
+	switch operator {
+	case "+":
+		sum := l + r
+		if (l > 0 && r > 0 && sum < 0) || (l < 0 && r < 0 && sum >= 0) {
+			return nil, overflow(l, operator, r)
+		}
+		return sum, nil
+	case "-":
+		difference := l - r
+		if (l >= 0 && r < 0 && difference < 0) || (l < 0 && r > 0 && difference >= 0) {
+			return nil, overflow(l, operator, r)
+		}
+		return difference, nil
+	case "*":
+		product, ok := multiply(l, r)
+		if !ok {
+			return nil, overflow(l, operator, r)
+		}
+		return product, nil
+	case "/":
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		if l == math.MinInt && r == -1 {
+			return nil, overflow(l, operator, r)
+		}
+		return l / r, nil
+	case "%":
+		if r == 0 {
+			return nil, fmt.Errorf("modulo by zero")
+		}
+		return l % r, nil
+	case "**":
+		if r < 0 {
+			return nil, fmt.Errorf("negative exponent %d in integer power", r)
+		}
+		result, ok := 1, true
+		for base, exp := l, r; exp > 0 && ok; exp >>= 1 {
+			if exp&1 == 1 {
+				result, ok = multiply(result, base)
+			}
+			// The last square is never used, it may overflow
+			if exp > 1 && ok {
+				base, ok = multiply(base, base)
+			}
+		}
+		if !ok {
+			return nil, overflow(l, operator, r)
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("unknown arithmetic operator: %s", operator)
+}
+
+func overflow(l int, operator string, r int) error {
+	return fmt.Errorf("integer overflow in %d %s %d", l, operator, r)
+}
+
+// multiply returns l * r, false when the product does not fit in an int.
+func multiply(l, r int) (int, bool) {
+	if l == 0 || r == 0 {
+		return 0, true
+	}
+	product := l * r
+	if product/r != l || (l == -1 && r == math.MinInt) || (r == -1 && l == math.MinInt) {
+		return 0, false
+	}
+	return product, true
+}
+
+func floatArithmetic(operator string, l, r float64) (interface{}, error) {
+	switch operator {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return l / r, nil
+	case "%":
+		if r == 0 {
+			return nil, fmt.Errorf("modulo by zero")
+		}
+		return math.Mod(l, r), nil
+	case "**":
+		return math.Pow(l, r), nil
+	}
+	return nil, fmt.Errorf("unknown arithmetic operator: %s", operator)
+}
+
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	}
+	return 0, fmt.Errorf("not a number: %T", value)
+}
+
+// Convert applies a cast or conversion operator to an int or float64 value.
+// "float" gives a float64, "int" truncates toward zero and "round", "floor"
+// and "ceil" round to an int.
+func Convert(operator string, value interface{}) (interface{}, error) {
+	f, err := toFloat(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operand type for %s: %T", operator, value)
+	}
+
+	if operator == "float" {
+		return f, nil
+	}
+	if i, ok := value.(int); ok {
+		return i, nil
+	}
+
+	switch operator {
+	case "int":
+		f = math.Trunc(f)
+	case "round":
+		f = math.Round(f)
+	case "floor":
+		f = math.Floor(f)
+	case "ceil":
+		f = math.Ceil(f)
+	default:
+		return nil, fmt.Errorf("unknown conversion operator: %s", operator)
+	}
+
+	if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return nil, fmt.Errorf("%s: value %v out of int range", operator, value)
+	}
+	return int(f), nil
+}
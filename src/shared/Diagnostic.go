@@ -0,0 +1,41 @@
+package shared
+
+import "fmt"
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// Diagnostic is a problem found in a program after it has been parsed.
+type Diagnostic struct {
+	Severity Severity
+	File     string // the imported module the problem is in, empty for the program itself
+	Line     int
+	Column   int
+	Message  string
+	Rule     string // the lint rule that found the problem, empty for the compiler's own checks
+}
+
+func (d Diagnostic) String() string {
+	position := fmt.Sprintf("line %d, column %d", d.Line, d.Column)
+	if d.File != "" {
+		position = d.File + ": " + position
+	}
+	message := fmt.Sprintf("%s: %s: %s", position, d.Severity, d.Message)
+	if d.Rule != "" {
+		message += " (" + d.Rule + ")"
+	}
+	return message
+}
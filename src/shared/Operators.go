@@ -0,0 +1,117 @@
+package shared
+
+// OperandKind describes how the VM interprets one operand of a quadruple.
+type OperandKind int
+
+const (
+	OperandNone        OperandKind = iota
+	OperandAddress                 // a memory address
+	OperandAddressList             // a list of memory addresses ([]int)
+	OperandQuad                    // the index of a quadruple
+	OperandQuadList                // a list of quadruple indices ([]int)
+	OperandValue                   // a plain integer, e.g. a parameter index
+	OperandName                    // a function name
+	OperandReference               // the address of a variable passed by reference, the callee may assign it
+)
+
+// OperatorInfo describes the operands of an operator. Address results are
+// written by the operator, address operands are read. A reference operand
+// is neither, what happens to it is up to the function it is passed to.
+type OperatorInfo struct {
+	Left   OperandKind
+	Right  OperandKind
+	Result OperandKind
+}
+
+var binaryOperator = OperatorInfo{Left: OperandAddress, Right: OperandAddress, Result: OperandAddress}
+
+var Operators = map[string]OperatorInfo{
+	"+":         binaryOperator,
+	"-":         binaryOperator,
+	"*":         binaryOperator,
+	"/":         binaryOperator,
+	"%":         binaryOperator,
+	"**":        binaryOperator,
+	"<":         binaryOperator,
+	">":         binaryOperator,
+	"==":        binaryOperator,
+	"!=":        binaryOperator,
+	"=":         {Left: OperandAddress, Result: OperandAddress},
+	"int":       {Left: OperandAddress, Result: OperandAddress},
+	"float":     {Left: OperandAddress, Result: OperandAddress},
+	"round":     {Left: OperandAddress, Result: OperandAddress},
+	"floor":     {Left: OperandAddress, Result: OperandAddress},
+	"ceil":      {Left: OperandAddress, Result: OperandAddress},
+	"print":     {Left: OperandAddressList},
+	"goto":      {Result: OperandQuad},
+	"gotof":     {Left: OperandAddress, Result: OperandQuad},
+	"jumptable": {Left: OperandAddress, Right: OperandValue, Result: OperandQuadList},
+	"era":       {Left: OperandName},
+	"param":     {Left: OperandAddress, Right: OperandValue},
+	"paramref":  {Left: OperandReference, Right: OperandValue},
+	"gosub":     {Left: OperandName, Right: OperandQuad, Result: OperandQuad},
+	"endproc":   {},
+
+	// frame creates the activation record of the main section, Left and Right
+	// are its number of int and float locals
+	"frame": {Left: OperandValue, Right: OperandValue},
+
+	// callbuiltin calls a Go builtin by name, Result is nil for void builtins
+	"callbuiltin": {Left: OperandName, Right: OperandAddressList, Result: OperandAddress},
+
+	// callnative calls a host function registered on the program, same operands as callbuiltin
+	"callnative": {Left: OperandName, Right: OperandAddressList, Result: OperandAddress},
+}
+
+// ReadAddresses returns the memory addresses read by the quadruple.
+func (q Quadruple) ReadAddresses() []int {
+	info := Operators[q.Operator]
+	addresses := make([]int, 0)
+	for _, operand := range []struct {
+		kind  OperandKind
+		value interface{}
+	}{{info.Left, q.LeftOp}, {info.Right, q.RightOp}} {
+		switch operand.kind {
+		case OperandAddress:
+			addresses = append(addresses, operand.value.(int))
+		case OperandAddressList:
+			addresses = append(addresses, operand.value.([]int)...)
+		}
+	}
+	return addresses
+}
+
+// WrittenAddress returns the memory address written by the quadruple, if any.
+func (q Quadruple) WrittenAddress() (int, bool) {
+	if Operators[q.Operator].Result != OperandAddress {
+		return 0, false
+	}
+	addr, ok := q.Result.(int)
+	return addr, ok
+}
+
+// ReferencedAddresses returns the memory addresses the quadruple passes by
+// reference.
+func (q Quadruple) ReferencedAddresses() []int {
+	info := Operators[q.Operator]
+	addresses := make([]int, 0)
+	if info.Left == OperandReference {
+		addresses = append(addresses, q.LeftOp.(int))
+	}
+	if info.Right == OperandReference {
+		addresses = append(addresses, q.RightOp.(int))
+	}
+	return addresses
+}
+
+// JumpTargets returns the quads the quadruple may transfer control to,
+// besides falling through to the next one.
+func (q Quadruple) JumpTargets() []int {
+	switch Operators[q.Operator].Result {
+	case OperandQuad:
+		return []int{q.Result.(int)}
+	case OperandQuadList:
+		return q.Result.([]int)
+	}
+	return nil
+}
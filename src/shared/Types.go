@@ -1,5 +1,7 @@
 package shared
 
+import "strings"
+
 type Type int
 
 const (
@@ -7,6 +9,8 @@ const (
 	TypeFloat
 	TypeString
 	TypeError
+	TypeVoid   // the result of a call that returns nothing
+	TypeStruct // a value of a struct type, its fields hold the data
 )
 
 func (t Type) String() string {
@@ -17,17 +21,80 @@ func (t Type) String() string {
 		return "float"
 	case TypeString:
 		return "string"
+	case TypeVoid:
+		return "void"
+	case TypeStruct:
+		return "struct"
 	default:
 		return "error"
 	}
 }
 
 type Variable struct {
-	Name    string
-	Type    Type
-	Line    int
-	Column  int
-	Address int
+	Name     string
+	Type     Type
+	Line     int
+	Column   int
+	Address  int
+	Constant bool       // declared with const, Address points to the constant pool
+	Struct   string     // the struct type when Type is TypeStruct
+	Fields   []Variable // the fields of a struct value, it has no address of its own
+	Ref      bool       // a parameter passed by reference
+}
+
+// Field returns the field of a struct value at path, a dot separated list
+// of field names like "origin.x".
+func (v Variable) Field(path string) (Variable, bool) {
+	for _, name := range strings.Split(path, ".") {
+		found := false
+		for _, field := range v.Fields {
+			if field.Name == name {
+				v, found = field, true
+				break
+			}
+		}
+		if !found {
+			return Variable{}, false
+		}
+	}
+	return v, true
+}
+
+// Scalars returns the int and float variables that hold the values of vars
+// in order, the fields of a struct are listed one by one.
+func Scalars(vars []Variable) []Variable {
+	scalars := make([]Variable, 0, len(vars))
+	for _, v := range vars {
+		if v.Type == TypeStruct {
+			scalars = append(scalars, Scalars(v.Fields)...)
+		} else {
+			scalars = append(scalars, v)
+		}
+	}
+	return scalars
+}
+
+// Argument is an argument of a call as seen by the type checker. Variable is
+// set when the argument names a variable, which can be passed by reference.
+type Argument struct {
+	Type     Type
+	Variable *Variable
+}
+
+// StructField is a field in the definition of a struct type.
+type StructField struct {
+	Name   string
+	Type   Type
+	Struct string // the struct type when Type is TypeStruct
+}
+
+// StructDef is a struct type declared with type Name struct { ... }.
+type StructDef struct {
+	Name   string
+	Fields []StructField
+	Line   int
+	Column int
+	Module string // the imported module that declares it, empty for the unit being compiled
 }
 
 type Function struct {
@@ -38,6 +105,7 @@ type Function struct {
 	StartQuad        int
 	IntVarsCounter   int
 	FloatVarsCounter int
+	Module           string // the imported module that declares it, empty for the unit being compiled
 }
 
 type FunctionInfo struct {
@@ -91,4 +159,6 @@ type Quadruple struct {
 	LeftOp   interface{} // Left operand
 	RightOp  interface{} // Right operand
 	Result   interface{} // Where the result will be stored
+	Line     int         // Source position of the statement that generated the quad
+	Column   int
 }
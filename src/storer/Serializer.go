@@ -4,11 +4,20 @@ import (
 	"encoding/gob"
 	"fmt"
 	"os"
+	"pogo/src/linker"
 	"pogo/src/semantic"
 	"pogo/src/shared"
 	"pogo/src/virtualmachine"
 )
 
+// objectFormat identifies object files, executables have no format field.
+const objectFormat = "pogo-object/1"
+
+type SerializedObject struct {
+	Format string
+	Unit   *linker.Unit
+}
+
 type SerializedVMData struct {
 	Quadruples    []shared.Quadruple
 	Functions     map[string]shared.FunctionInfo
@@ -16,25 +25,17 @@ type SerializedVMData struct {
 }
 
 func SaveCompiledData(quads []shared.Quadruple, SymbolTable *semantic.SymbolTable, memoryManager *virtualmachine.MemoryManager, filename string) error {
-	functions := make(map[string]shared.FunctionInfo)
-	for name, symbol := range SymbolTable.GetGlobalScope() {
-		if function, ok := symbol.(shared.Function); ok {
-			functions[name] = shared.FunctionInfo{
-				Name:           function.Name,
-				StartQuad:      function.StartQuad,
-				IntVarsCount:   function.IntVarsCounter,
-				FloatVarsCount: function.FloatVarsCounter,
-				Parameters:     function.Parameters,
-			}
-		}
-	}
-
 	vmData := SerializedVMData{
 		Quadruples:    quads,
-		Functions:     functions,
+		Functions:     SymbolTable.GetFunctionInfos(),
 		MemoryManager: memoryManager,
 	}
 
+	return WriteCompiledData(vmData, filename)
+}
+
+// WriteCompiledData writes already collected program data to filename.
+func WriteCompiledData(vmData SerializedVMData, filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("error creating output file: %v", err)
@@ -43,14 +44,14 @@ func SaveCompiledData(quads []shared.Quadruple, SymbolTable *semantic.SymbolTabl
 
 	encoder := gob.NewEncoder(file)
 	if err := encoder.Encode(vmData); err != nil {
-		// return fmt.Errorf("error encoding data: %v", err)
+		return fmt.Errorf("error encoding data: %v", err)
 	}
 
 	return nil
 }
 
-func LoadCompiledData(filename string) (*virtualmachine.VirtualMachine, error) {
-
+// ReadCompiledData reads the program data written by SaveCompiledData.
+func ReadCompiledData(filename string) (*SerializedVMData, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, fmt.Errorf("error opening file: %v", err)
@@ -64,6 +65,15 @@ func LoadCompiledData(filename string) (*virtualmachine.VirtualMachine, error) {
 		return nil, fmt.Errorf("error decoding data: %v", err)
 	}
 
+	return &vmData, nil
+}
+
+func LoadCompiledData(filename string) (*virtualmachine.VirtualMachine, error) {
+	vmData, err := ReadCompiledData(filename)
+	if err != nil {
+		return nil, err
+	}
+
 	memManager := vmData.MemoryManager
 	vm := virtualmachine.NewVirtualMachine(vmData.Quadruples, memManager)
 
@@ -71,3 +81,32 @@ func LoadCompiledData(filename string) (*virtualmachine.VirtualMachine, error) {
 
 	return vm, nil
 }
+
+// SaveObject writes a compiled unit that still has to be linked.
+func SaveObject(unit *linker.Unit, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("error creating output file: %v", err)
+	}
+	defer file.Close()
+
+	if err := gob.NewEncoder(file).Encode(SerializedObject{Format: objectFormat, Unit: unit}); err != nil {
+		return fmt.Errorf("error encoding object: %v", err)
+	}
+	return nil
+}
+
+// LoadObject reads an object file written by SaveObject.
+func LoadObject(filename string) (*linker.Unit, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %v", err)
+	}
+	defer file.Close()
+
+	var object SerializedObject
+	if err := gob.NewDecoder(file).Decode(&object); err != nil || object.Format != objectFormat {
+		return nil, fmt.Errorf("%s is not a pogo object file", filename)
+	}
+	return object.Unit, nil
+}
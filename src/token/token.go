@@ -141,21 +141,32 @@ var TokMap = TokenMap{
 		"assignOp",
 		"closeBrace",
 		"closeParan",
+		"dot",
 		"expressionOp",
 		"floatLit",
 		"id",
 		"intLit",
 		"kwdBegin",
+		"kwdCase",
+		"kwdConst",
+		"kwdDefault",
 		"kwdElse",
 		"kwdEnd",
 		"kwdFunc",
 		"kwdIf",
+		"kwdImport",
+		"kwdModule",
 		"kwdPrint",
 		"kwdProgram",
+		"kwdRef",
+		"kwdStruct",
+		"kwdSwitch",
+		"kwdType",
 		"kwdVars",
 		"kwdWhile",
 		"openBrace",
 		"openParan",
+		"powOp",
 		"relOp",
 		"repeatTerminator",
 		"stringLit",
@@ -171,27 +182,38 @@ var TokMap = TokenMap{
 		"assignOp":         2,
 		"closeBrace":       3,
 		"closeParan":       4,
-		"expressionOp":     5,
-		"floatLit":         6,
-		"id":               7,
-		"intLit":           8,
-		"kwdBegin":         9,
-		"kwdElse":          10,
-		"kwdEnd":           11,
-		"kwdFunc":          12,
-		"kwdIf":            13,
-		"kwdPrint":         14,
-		"kwdProgram":       15,
-		"kwdVars":          16,
-		"kwdWhile":         17,
-		"openBrace":        18,
-		"openParan":        19,
-		"relOp":            20,
-		"repeatTerminator": 21,
-		"stringLit":        22,
-		"termOp":           23,
-		"terminator":       24,
-		"type":             25,
-		"typeAssignOp":     26,
+		"dot":              5,
+		"expressionOp":     6,
+		"floatLit":         7,
+		"id":               8,
+		"intLit":           9,
+		"kwdBegin":         10,
+		"kwdCase":          11,
+		"kwdConst":         12,
+		"kwdDefault":       13,
+		"kwdElse":          14,
+		"kwdEnd":           15,
+		"kwdFunc":          16,
+		"kwdIf":            17,
+		"kwdImport":        18,
+		"kwdModule":        19,
+		"kwdPrint":         20,
+		"kwdProgram":       21,
+		"kwdRef":           22,
+		"kwdStruct":        23,
+		"kwdSwitch":        24,
+		"kwdType":          25,
+		"kwdVars":          26,
+		"kwdWhile":         27,
+		"openBrace":        28,
+		"openParan":        29,
+		"powOp":            30,
+		"relOp":            31,
+		"repeatTerminator": 32,
+		"stringLit":        33,
+		"termOp":           34,
+		"terminator":       35,
+		"type":             36,
+		"typeAssignOp":     37,
 	},
 }
@@ -46,6 +46,14 @@ type FunctionMemorySegment struct {
 	floatVarsCount int
 }
 
+// reference is held by a parameter passed by reference, it is the address of
+// the argument and the index of the frame it lives in. Globals belong to no
+// frame, their frame is -1.
+type reference struct {
+	frame   int
+	address int
+}
+
 type MemoryManager struct {
 	globalMemory   []interface{}
 	GlobalIntPtr   int
@@ -65,6 +73,14 @@ type MemoryManager struct {
 
 	memoryStack    []FunctionMemorySegment
 	currentSegment *FunctionMemorySegment
+
+	// preparedSegments are the frames of calls whose arguments are being
+	// stored, the last one becomes the current segment when it is called
+	preparedSegments []FunctionMemorySegment
+
+	// stored is called with every value Store and StoreParam write, a Trace
+	// sets it while a program runs
+	stored func(address int, value interface{})
 }
 
 func NewMemoryManager() *MemoryManager {
@@ -96,6 +112,19 @@ func (mm *MemoryManager) InitializeMemory() {
 	mm.tempMemory = make([]interface{}, tempSize)
 }
 
+// Clone returns a memory manager with the same layout and constants but no
+// runtime state, so the same compiled program can run several times, also
+// concurrently. The constant tables are shared and must not be modified.
+func (mm *MemoryManager) Clone() *MemoryManager {
+	clone := *mm
+	clone.globalMemory = nil
+	clone.tempMemory = nil
+	clone.memoryStack = make([]FunctionMemorySegment, 0)
+	clone.currentSegment = nil
+	clone.preparedSegments = nil
+	return &clone
+}
+
 func (mm *MemoryManager) AllocateGlobal(varType shared.Type) (int, error) {
 	switch varType {
 	case shared.TypeInt:
@@ -169,6 +198,12 @@ func (mm *MemoryManager) AllocateConstant(value string) (int, error) {
 	return -1, fmt.Errorf("invalid constant value: %s", value)
 }
 
+// GetConstant returns the value stored at a constant pool address.
+func (mm *MemoryManager) GetConstant(address int) (interface{}, bool) {
+	value, exists := mm.ConstantMapLoad[address-CONSTANT_START]
+	return value, exists
+}
+
 func (mm *MemoryManager) AllocateLocal(varType shared.Type) (int, error) {
 	switch varType {
 	case shared.TypeInt:
@@ -214,31 +249,36 @@ func (mm *MemoryManager) AllocateStringAddress(value string) (int, error) {
 }
 
 func (mm *MemoryManager) Store(address int, value interface{}) error {
+	if err := mm.storeValue(address, value); err != nil {
+		return err
+	}
+	if mm.stored != nil {
+		mm.stored(address, value)
+	}
+	return nil
+}
+
+func (mm *MemoryManager) storeValue(address int, value interface{}) error {
 	if address < 0 || address >= TOTAL_MEMORY_SIZE {
 		return fmt.Errorf("memory access out of bounds: %d", address)
 	}
-	// fmt.Println("entered here:  with address ", address, "and ", value)
+
+	value, err := checkSegmentType(address, value)
+	if err != nil {
+		return err
+	}
 
 	var segment *[]interface{}
 	var offset int
 
 	if address >= LOCAL_START && address < LOCAL_START+MEMORY_SEGMENT_SIZE {
-		if address < LOCAL_FLOAT_START {
-			offset = address - LOCAL_START
-		} else {
-			offset = address - LOCAL_FLOAT_START + mm.currentSegment.intVarsCount
-		}
-
 		if mm.currentSegment == nil {
 			return fmt.Errorf("no active function segment")
 		}
-
-		if offset >= len(mm.currentSegment.localMemory) {
-			return fmt.Errorf("local memory access out of bounds: %d", address)
+		if ref, ok := mm.currentSegment.reference(address); ok {
+			return mm.storeReference(ref, value)
 		}
-		// Store directly in the dynamic local memory
-		mm.currentSegment.localMemory[offset] = value
-		return nil
+		return mm.currentSegment.store(address, value)
 	}
 
 	switch {
@@ -264,6 +304,29 @@ func (mm *MemoryManager) Store(address int, value interface{}) error {
 	return nil
 }
 
+// checkSegmentType makes sure a value matches the type of the segment it is
+// stored in. Ints stored in a float segment are promoted to float64.
+func checkSegmentType(address int, value interface{}) (interface{}, error) {
+	isFloat := (address >= GLOBAL_FLOAT_START && address <= GLOBAL_FLOAT_END) ||
+		(address >= LOCAL_FLOAT_START && address <= LOCAL_FLOAT_END) ||
+		(address >= TEMP_FLOAT_START && address <= TEMP_FLOAT_END)
+
+	switch v := value.(type) {
+	case int:
+		if isFloat {
+			return float64(v), nil
+		}
+		return v, nil
+	case float64:
+		if !isFloat {
+			return nil, fmt.Errorf("cannot store float value %v at int address %d", v, address)
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("cannot store value of type %T at address %d", value, address)
+	}
+}
+
 func (mm *MemoryManager) Load(address int) (interface{}, error) {
 	if address < 0 || address >= TOTAL_MEMORY_SIZE {
 		return nil, fmt.Errorf("memory access out of bounds: %d", address)
@@ -273,21 +336,15 @@ func (mm *MemoryManager) Load(address int) (interface{}, error) {
 	var offset int
 
 	if address >= LOCAL_START && address < LOCAL_START+MEMORY_SEGMENT_SIZE {
-		if address < LOCAL_FLOAT_START {
-			offset = address - LOCAL_START
-		} else {
-			offset = (address - LOCAL_FLOAT_START) + mm.currentSegment.intVarsCount
-		}
-
 		if mm.currentSegment == nil {
 			return nil, fmt.Errorf("no active function segment")
 		}
-		if offset >= len(mm.currentSegment.localMemory) {
-			return nil, fmt.Errorf("local memory access out of bounds: %d", address)
+		value, err := mm.currentSegment.load(address)
+		if err != nil {
+			return nil, err
 		}
-		value := mm.currentSegment.localMemory[offset]
-		if value == nil {
-			return nil, fmt.Errorf("accessing uninitialized memory at address %d", address)
+		if ref, ok := value.(reference); ok {
+			return mm.loadReference(ref)
 		}
 		return value, nil
 	}
@@ -325,6 +382,150 @@ func (mm *MemoryManager) Load(address int) (interface{}, error) {
 	return value, nil
 }
 
+// offset returns the index of a local address in the memory of the segment.
+func (fs *FunctionMemorySegment) offset(address int) (int, error) {
+	var offset int
+	if address < LOCAL_FLOAT_START {
+		offset = address - LOCAL_START
+	} else {
+		offset = address - LOCAL_FLOAT_START + fs.intVarsCount
+	}
+
+	if offset >= len(fs.localMemory) {
+		return -1, fmt.Errorf("local memory access out of bounds: %d", address)
+	}
+	return offset, nil
+}
+
+// load reads the value at a local address of the segment, which may be a
+// reference.
+func (fs *FunctionMemorySegment) load(address int) (interface{}, error) {
+	offset, err := fs.offset(address)
+	if err != nil {
+		return nil, err
+	}
+
+	value := fs.localMemory[offset]
+	if value == nil {
+		return nil, fmt.Errorf("accessing uninitialized memory at address %d", address)
+	}
+	return value, nil
+}
+
+// store writes a value to a local address of the segment.
+func (fs *FunctionMemorySegment) store(address int, value interface{}) error {
+	offset, err := fs.offset(address)
+	if err != nil {
+		return err
+	}
+	// Store directly in the dynamic local memory
+	fs.localMemory[offset] = value
+	return nil
+}
+
+// reference returns the reference held at a local address of the segment.
+func (fs *FunctionMemorySegment) reference(address int) (reference, bool) {
+	offset, err := fs.offset(address)
+	if err != nil {
+		return reference{}, false
+	}
+	ref, ok := fs.localMemory[offset].(reference)
+	return ref, ok
+}
+
+func (mm *MemoryManager) loadReference(ref reference) (interface{}, error) {
+	if ref.frame < 0 {
+		return mm.Load(ref.address)
+	}
+	return mm.memoryStack[ref.frame].load(ref.address)
+}
+
+// storeReference writes through a reference, the value must suit the
+// variable it refers to and not only the parameter holding the reference.
+func (mm *MemoryManager) storeReference(ref reference, value interface{}) error {
+	value, err := checkSegmentType(ref.address, value)
+	if err != nil {
+		return err
+	}
+	if ref.frame < 0 {
+		return mm.storeValue(ref.address, value)
+	}
+	return mm.memoryStack[ref.frame].store(ref.address, value)
+}
+
+// PrepareFunctionSegment creates the frame of a function that is about to be
+// called. The caller segment stays current while the arguments are stored
+// with StoreParam, PushPreparedSegment then makes it current.
+func (mm *MemoryManager) PrepareFunctionSegment(intCount, floatCount int) {
+	mm.preparedSegments = append(mm.preparedSegments, FunctionMemorySegment{
+		localMemory:    make([]interface{}, intCount+floatCount),
+		localIntPtr:    LOCAL_INT_START,
+		localFloatPtr:  LOCAL_FLOAT_START,
+		intVarsCount:   intCount,
+		floatVarsCount: floatCount,
+	})
+}
+
+// StoreParam stores an argument at a local address of the last prepared frame.
+func (mm *MemoryManager) StoreParam(address int, value interface{}) error {
+	if len(mm.preparedSegments) == 0 {
+		return fmt.Errorf("no function segment is being prepared")
+	}
+	if address < LOCAL_START || address >= TEMP_START {
+		return fmt.Errorf("parameter address %d is not a local address", address)
+	}
+
+	value, err := checkSegmentType(address, value)
+	if err != nil {
+		return err
+	}
+	if err := mm.preparedSegments[len(mm.preparedSegments)-1].store(address, value); err != nil {
+		return err
+	}
+	if mm.stored != nil {
+		mm.stored(address, value)
+	}
+	return nil
+}
+
+// StoreParamRef stores at a local address of the last prepared frame a
+// reference to the variable at address. A parameter that is itself passed by
+// reference is passed on as the reference it holds.
+func (mm *MemoryManager) StoreParamRef(param, address int) error {
+	if len(mm.preparedSegments) == 0 {
+		return fmt.Errorf("no function segment is being prepared")
+	}
+	if param < LOCAL_START || param >= TEMP_START {
+		return fmt.Errorf("parameter address %d is not a local address", param)
+	}
+
+	ref := reference{frame: -1, address: address}
+	if address >= LOCAL_START && address < TEMP_START {
+		if mm.currentSegment == nil {
+			return fmt.Errorf("no active function segment")
+		}
+		if existing, ok := mm.currentSegment.reference(address); ok {
+			ref = existing
+		} else {
+			ref.frame = len(mm.memoryStack) - 1
+		}
+	}
+	return mm.preparedSegments[len(mm.preparedSegments)-1].store(param, ref)
+}
+
+// PushPreparedSegment makes the last prepared frame the current segment.
+func (mm *MemoryManager) PushPreparedSegment() error {
+	if len(mm.preparedSegments) == 0 {
+		return fmt.Errorf("no function segment is being prepared")
+	}
+
+	last := len(mm.preparedSegments) - 1
+	mm.memoryStack = append(mm.memoryStack, mm.preparedSegments[last])
+	mm.preparedSegments = mm.preparedSegments[:last]
+	mm.currentSegment = &mm.memoryStack[len(mm.memoryStack)-1]
+	return nil
+}
+
 func (mm *MemoryManager) PushNewFunctionSegment(isFixed bool, intCount, floatCount int) {
 	var size int
 	if isFixed {
@@ -0,0 +1,213 @@
+package virtualmachine
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sort"
+)
+
+// WritePprof writes the profile in the gzipped protocol buffer format read
+// by go tool pprof. Its samples are the call stacks of the run down to the
+// source line, with the quads executed and the time spent on each.
+func (p *Profile) WritePprof(w io.Writer) error {
+	pb := &pprofBuilder{
+		strings:   map[string]int64{"": 0},
+		functions: make(map[string]uint64),
+		locations: make(map[callSite]uint64),
+	}
+	pb.stringTable = []string{""}
+	pb.filename = pb.str(p.Filename)
+
+	var profile protoBuffer
+	for _, sampleType := range [][2]string{{"quads", "count"}, {"time", "nanoseconds"}} {
+		profile.message(1, pb.valueType(sampleType[0], sampleType[1]))
+	}
+
+	if p.root != nil {
+		pb.samples(&profile, p.root, nil)
+	}
+
+	for _, location := range pb.locationList {
+		profile.message(4, location)
+	}
+	for _, function := range pb.functionList {
+		profile.message(5, function)
+	}
+
+	// Strings are referred to by index, so the table goes after everything
+	// that adds to it
+	timeType := pb.valueType("time", "nanoseconds")
+	defaultType := pb.str("time")
+	for _, s := range pb.stringTable {
+		profile.bytes(6, []byte(s))
+	}
+
+	profile.int(9, p.start.UnixNano())
+	profile.int(10, int64(p.Duration))
+	profile.message(11, timeType)
+	profile.int(12, 1)
+	profile.int(14, defaultType)
+
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write(profile.Bytes()); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+type pprofBuilder struct {
+	filename     int64
+	strings      map[string]int64
+	stringTable  []string
+	functions    map[string]uint64
+	functionList [][]byte
+	locations    map[callSite]uint64
+	locationList [][]byte
+}
+
+func (pb *pprofBuilder) str(s string) int64 {
+	index, ok := pb.strings[s]
+	if !ok {
+		index = int64(len(pb.stringTable))
+		pb.strings[s] = index
+		pb.stringTable = append(pb.stringTable, s)
+	}
+	return index
+}
+
+func (pb *pprofBuilder) valueType(typ, unit string) []byte {
+	var vt protoBuffer
+	vt.int(1, pb.str(typ))
+	vt.int(2, pb.str(unit))
+	return vt.Bytes()
+}
+
+func (pb *pprofBuilder) function(name string) uint64 {
+	id, ok := pb.functions[name]
+	if !ok {
+		id = uint64(len(pb.functionList) + 1)
+		pb.functions[name] = id
+
+		// pprof drops what is between angle brackets from names
+		label := name
+		if name == ProgramBody {
+			label = "program"
+		}
+
+		var function protoBuffer
+		function.uint(1, id)
+		function.int(2, pb.str(label))
+		function.int(3, pb.str(label))
+		function.int(4, pb.filename)
+		pb.functionList = append(pb.functionList, function.Bytes())
+	}
+	return id
+}
+
+func (pb *pprofBuilder) location(function string, line int) uint64 {
+	site := callSite{function: function, line: line}
+	id, ok := pb.locations[site]
+	if !ok {
+		id = uint64(len(pb.locationList) + 1)
+		pb.locations[site] = id
+
+		var l protoBuffer
+		l.uint(1, pb.function(function))
+		l.int(2, int64(line))
+
+		var location protoBuffer
+		location.uint(1, id)
+		location.message(4, l.Bytes())
+		pb.locationList = append(pb.locationList, location.Bytes())
+	}
+	return id
+}
+
+// samples adds a sample for every line of node, callers holds the locations
+// of the calls that led to it, innermost first.
+func (pb *pprofBuilder) samples(profile *protoBuffer, node *callNode, callers []uint64) {
+	lines := make([]int, 0, len(node.lines))
+	for line := range node.lines {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+
+	for _, line := range lines {
+		counts := node.lines[line]
+		stack := append([]uint64{pb.location(node.function, line)}, callers...)
+
+		var sample protoBuffer
+		sample.packedUints(1, stack)
+		sample.packedInts(2, []int64{counts.quads, int64(counts.time)})
+		profile.message(2, sample.Bytes())
+	}
+
+	children := make([]*callNode, 0, len(node.children))
+	for _, child := range node.children {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].line != children[j].line {
+			return children[i].line < children[j].line
+		}
+		return children[i].function < children[j].function
+	})
+
+	for _, child := range children {
+		site := pb.location(node.function, child.line)
+		pb.samples(profile, child, append([]uint64{site}, callers...))
+	}
+}
+
+// protoBuffer encodes the fields of a protocol buffer message.
+type protoBuffer struct {
+	bytes.Buffer
+}
+
+func (b *protoBuffer) varint(x uint64) {
+	for x >= 0x80 {
+		b.WriteByte(byte(x) | 0x80)
+		x >>= 7
+	}
+	b.WriteByte(byte(x))
+}
+
+func (b *protoBuffer) tag(field int, wireType uint64) {
+	b.varint(uint64(field)<<3 | wireType)
+}
+
+func (b *protoBuffer) uint(field int, x uint64) {
+	b.tag(field, 0)
+	b.varint(x)
+}
+
+func (b *protoBuffer) int(field int, x int64) {
+	b.uint(field, uint64(x))
+}
+
+func (b *protoBuffer) bytes(field int, data []byte) {
+	b.tag(field, 2)
+	b.varint(uint64(len(data)))
+	b.Write(data)
+}
+
+func (b *protoBuffer) message(field int, data []byte) {
+	b.bytes(field, data)
+}
+
+func (b *protoBuffer) packedUints(field int, xs []uint64) {
+	var packed protoBuffer
+	for _, x := range xs {
+		packed.varint(x)
+	}
+	b.bytes(field, packed.Bytes())
+}
+
+func (b *protoBuffer) packedInts(field int, xs []int64) {
+	var packed protoBuffer
+	for _, x := range xs {
+		packed.varint(uint64(x))
+	}
+	b.bytes(field, packed.Bytes())
+}
@@ -0,0 +1,239 @@
+package virtualmachine
+
+import (
+	"fmt"
+	"io"
+	"pogo/src/shared"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// ProgramBody names the code outside of any function in profiles, traces
+// and control flow graphs: the global initializers and the main section.
+const ProgramBody = "<program>"
+
+// Profile collects what a run of the VM spends its time on. A VM with a
+// Profile counts every quad it executes and times it, which makes it run
+// several times slower.
+type Profile struct {
+	Filename  string // the source file, named in pprof profiles
+	Quads     int
+	Duration  time.Duration
+	Opcodes   map[string]int // executed quads per operator
+	Lines     map[int]int    // executed quads per source line, 0 for quads without one
+	Functions map[string]*FunctionProfile
+
+	root   *callNode
+	frames []profileFrame
+	active map[string]int // frames of every function on the stack
+	start  time.Time
+	last   time.Time // when the previous quad was done
+}
+
+// FunctionProfile is what a function took over a whole run. A recursive
+// function is timed from its outermost call, so Total never counts the same
+// time twice.
+type FunctionProfile struct {
+	Name  string
+	Calls int
+	Quads int
+	Self  time.Duration // running its own quads, the Self of every function adds up to Duration
+	Total time.Duration // Self plus the functions it calls
+}
+
+type profileFrame struct {
+	node  *callNode
+	start time.Time
+}
+
+// callNode is a function reached through a chain of calls. Its counts are
+// kept per line of the function, the stacks a pprof profile is made of.
+type callNode struct {
+	function string
+	line     int // the line of the call in the parent
+	parent   *callNode
+	children map[callSite]*callNode
+	lines    map[int]*lineCounts
+}
+
+type callSite struct {
+	function string
+	line     int
+}
+
+type lineCounts struct {
+	quads int64
+	time  time.Duration
+}
+
+func NewProfile() *Profile {
+	return &Profile{
+		Opcodes:   make(map[string]int),
+		Lines:     make(map[int]int),
+		Functions: make(map[string]*FunctionProfile),
+		active:    make(map[string]int),
+	}
+}
+
+func newCallNode(function string, line int, parent *callNode) *callNode {
+	return &callNode{
+		function: function,
+		line:     line,
+		parent:   parent,
+		children: make(map[callSite]*callNode),
+		lines:    make(map[int]*lineCounts),
+	}
+}
+
+func (p *Profile) function(name string) *FunctionProfile {
+	function, ok := p.Functions[name]
+	if !ok {
+		function = &FunctionProfile{Name: name}
+		p.Functions[name] = function
+	}
+	return function
+}
+
+// begin starts the program body.
+func (p *Profile) begin() {
+	p.start = time.Now()
+	p.last = p.start
+	p.root = newCallNode(ProgramBody, 0, nil)
+	p.push(p.root, p.start)
+}
+
+// end stops the frames still running, the program body and any function a
+// runtime error left.
+func (p *Profile) end() {
+	now := time.Now()
+	if len(p.frames) > 0 {
+		p.function(p.frames[len(p.frames)-1].node.function).Self += now.Sub(p.last)
+	}
+	for len(p.frames) > 0 {
+		p.pop(now)
+	}
+	p.Duration = now.Sub(p.start)
+}
+
+func (p *Profile) push(node *callNode, now time.Time) {
+	p.frames = append(p.frames, profileFrame{node: node, start: now})
+	p.active[node.function]++
+	p.function(node.function).Calls++
+}
+
+func (p *Profile) pop(now time.Time) {
+	frame := p.frames[len(p.frames)-1]
+	p.frames = p.frames[:len(p.frames)-1]
+
+	name := frame.node.function
+	p.active[name]--
+	if p.active[name] == 0 {
+		p.function(name).Total += now.Sub(frame.start)
+	}
+}
+
+// record counts a quad the running function has just executed, it took the
+// time since the previous one was done. callee is the function a gosub
+// enters, the top of the VM's function stack.
+func (p *Profile) record(quad shared.Quadruple, callee string) {
+	now := time.Now()
+	elapsed := now.Sub(p.last)
+	p.last = now
+	node := p.frames[len(p.frames)-1].node
+
+	p.Quads++
+	p.Opcodes[quad.Operator]++
+	p.Lines[quad.Line]++
+
+	function := p.function(node.function)
+	function.Quads++
+	function.Self += elapsed
+
+	counts, ok := node.lines[quad.Line]
+	if !ok {
+		counts = &lineCounts{}
+		node.lines[quad.Line] = counts
+	}
+	counts.quads++
+	counts.time += elapsed
+
+	switch quad.Operator {
+	case "gosub":
+		site := callSite{function: callee, line: quad.Line}
+		child, ok := node.children[site]
+		if !ok {
+			child = newCallNode(callee, quad.Line, node)
+			node.children[site] = child
+		}
+		p.push(child, now)
+	case "endproc":
+		// The program body is never popped, a stray endproc can't end it
+		if len(p.frames) > 1 {
+			p.pop(now)
+		}
+	}
+}
+
+// WriteReport writes the profile as text: the functions by total time, the
+// operators by count and the source lines in order.
+func (p *Profile) WriteReport(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "%d quads in %v\n", p.Quads, p.Duration)
+
+	functions := make([]*FunctionProfile, 0, len(p.Functions))
+	for _, function := range p.Functions {
+		functions = append(functions, function)
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].Total != functions[j].Total {
+			return functions[i].Total > functions[j].Total
+		}
+		return functions[i].Name < functions[j].Name
+	})
+
+	fmt.Fprintln(tw, "\nfunction\tcalls\tquads\tself\ttotal\t")
+	for _, function := range functions {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%v\t%v\t\n", function.Name, function.Calls, function.Quads,
+			function.Self.Round(time.Microsecond), function.Total.Round(time.Microsecond))
+	}
+
+	operators := make([]string, 0, len(p.Opcodes))
+	for operator := range p.Opcodes {
+		operators = append(operators, operator)
+	}
+	sort.Slice(operators, func(i, j int) bool {
+		if p.Opcodes[operators[i]] != p.Opcodes[operators[j]] {
+			return p.Opcodes[operators[i]] > p.Opcodes[operators[j]]
+		}
+		return operators[i] < operators[j]
+	})
+
+	fmt.Fprintln(tw, "\nopcode\tquads\t%\t")
+	for _, operator := range operators {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t\n", operator, p.Opcodes[operator], p.percent(p.Opcodes[operator]))
+	}
+
+	lines := make([]int, 0, len(p.Lines))
+	for line := range p.Lines {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+
+	fmt.Fprintln(tw, "\nline\tquads\t%\t")
+	for _, line := range lines {
+		label := fmt.Sprint(line)
+		if line == 0 {
+			label = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t\n", label, p.Lines[line], p.percent(p.Lines[line]))
+	}
+	return tw.Flush()
+}
+
+func (p *Profile) percent(quads int) string {
+	if p.Quads == 0 {
+		return "0.0"
+	}
+	return fmt.Sprintf("%.1f", 100*float64(quads)/float64(p.Quads))
+}
@@ -0,0 +1,174 @@
+package virtualmachine
+
+import (
+	"fmt"
+	"io"
+	"pogo/src/shared"
+	"strconv"
+	"strings"
+)
+
+// Trace writes a line for every quad a VM executes: its index, the function
+// running it, the operator, the operands with the values read from memory
+// and the values the quad stored, e.g.
+//
+//	5 fib: - 4000=3, 12001=1 => 8001=2
+//	11 fib: gosub fib, q12, q1
+//
+// Nothing in a line depends on timing, two runs of a program give the same
+// trace. The filters keep traces of long runs small.
+type Trace struct {
+	Output    io.Writer
+	Functions []string    // only the quads run by these functions, ProgramBody for the main section, all when empty
+	Ranges    []QuadRange // only the quads in one of the ranges, all when empty
+
+	running []string // the functions on the call stack
+	stores  []string // what the quad being traced stored
+}
+
+// QuadRange is the quads from First to Last, both included.
+type QuadRange struct {
+	First, Last int
+}
+
+// ParseQuadRange reads a range written "10-20", or "7" for a single quad.
+func ParseQuadRange(s string) (QuadRange, error) {
+	first, last, found := strings.Cut(strings.TrimSpace(s), "-")
+	r := QuadRange{}
+	var err error
+	if r.First, err = strconv.Atoi(first); err != nil {
+		return QuadRange{}, fmt.Errorf("invalid quad range '%s'", s)
+	}
+	r.Last = r.First
+	if found {
+		if r.Last, err = strconv.Atoi(last); err != nil || r.Last < r.First {
+			return QuadRange{}, fmt.Errorf("invalid quad range '%s'", s)
+		}
+	}
+	return r, nil
+}
+
+func (t *Trace) begin(mm *MemoryManager) {
+	t.running = []string{ProgramBody}
+	mm.stored = func(address int, value interface{}) {
+		t.stores = append(t.stores, fmt.Sprintf("%d=%s", address, traceValue(value)))
+	}
+}
+
+func (t *Trace) end(mm *MemoryManager) {
+	mm.stored = nil
+}
+
+func (t *Trace) traces(index int, function string) bool {
+	if len(t.Functions) > 0 {
+		found := false
+		for _, name := range t.Functions {
+			found = found || name == function
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(t.Ranges) == 0 {
+		return true
+	}
+	for _, r := range t.Ranges {
+		if index >= r.First && index <= r.Last {
+			return true
+		}
+	}
+	return false
+}
+
+// operands describes the operands of quad with the values its addresses
+// hold before it runs.
+func (t *Trace) operands(quad shared.Quadruple, mm *MemoryManager) string {
+	info := shared.Operators[quad.Operator]
+	operands := make([]string, 0, 3)
+	describe := func(kind shared.OperandKind, operand interface{}) {
+		switch kind {
+		case shared.OperandAddress, shared.OperandReference:
+			operands = append(operands, traceAddress(operand.(int), mm))
+		case shared.OperandAddressList:
+			for _, addr := range operand.([]int) {
+				operands = append(operands, traceAddress(addr, mm))
+			}
+		case shared.OperandQuad:
+			operands = append(operands, fmt.Sprintf("q%d", operand))
+		case shared.OperandQuadList:
+			targets := make([]string, 0)
+			for _, target := range operand.([]int) {
+				targets = append(targets, fmt.Sprintf("q%d", target))
+			}
+			operands = append(operands, "["+strings.Join(targets, " ")+"]")
+		case shared.OperandValue, shared.OperandName:
+			operands = append(operands, fmt.Sprint(operand))
+		}
+	}
+
+	describe(info.Left, quad.LeftOp)
+	describe(info.Right, quad.RightOp)
+	// Written addresses show up with the values stored in them
+	if info.Result != shared.OperandAddress {
+		describe(info.Result, quad.Result)
+	}
+	return strings.Join(operands, ", ")
+}
+
+func traceAddress(addr int, mm *MemoryManager) string {
+	value, err := mm.Load(addr)
+	if err != nil || value == nil {
+		return fmt.Sprintf("%d=?", addr)
+	}
+	return fmt.Sprintf("%d=%s", addr, traceValue(value))
+}
+
+func traceValue(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// traced wraps execute so it writes a line for each quad it executes.
+func (vm *VirtualMachine) traced(execute func(shared.Quadruple) error) func(shared.Quadruple) error {
+	t := vm.Trace
+	return func(quad shared.Quadruple) error {
+		index := vm.instructionPointer
+		function := t.running[len(t.running)-1]
+		callee, _ := vm.functionStack.Top().(string)
+
+		traced := t.traces(index, function)
+		var line string
+		if traced {
+			line = fmt.Sprintf("%d %s: %s", index, function, quad.Operator)
+			if operands := t.operands(quad, vm.memoryManager); operands != "" {
+				line += " " + operands
+			}
+		}
+
+		t.stores = t.stores[:0]
+		err := execute(quad)
+
+		switch quad.Operator {
+		case "gosub":
+			t.running = append(t.running, callee)
+		case "endproc":
+			if len(t.running) > 1 {
+				t.running = t.running[:len(t.running)-1]
+			}
+		}
+
+		if !traced {
+			return err
+		}
+		if len(t.stores) > 0 {
+			line += " => " + strings.Join(t.stores, ", ")
+		}
+		if _, writeErr := fmt.Fprintln(t.Output, line); writeErr != nil && err == nil {
+			return fmt.Errorf("writing trace: %v", writeErr)
+		}
+		return err
+	}
+}
@@ -1,15 +1,29 @@
 package virtualmachine
 
 import (
+	"cmp"
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"pogo/src/builtins"
+	"pogo/src/native"
 	"pogo/src/shared"
 	"strings"
 )
 
+// cancelCheckInterval is how many instructions run between checks of the
+// context passed to ExecuteContext.
+const cancelCheckInterval = 1024
+
 type VirtualMachine struct {
 	quads              []shared.Quadruple
 	memoryManager      *MemoryManager
 	Functions          map[string]shared.FunctionInfo
+	Output             io.Writer        // where print writes, os.Stdout by default
+	Natives            *native.Registry // host functions called by callnative
+	Profile            *Profile         // filled with the counts and times of the run when set
+	Trace              *Trace           // writes every executed quad when set
 	instructionPointer int
 	returnPointer      *shared.Stack
 	functionStack      *shared.Stack
@@ -23,20 +37,44 @@ func NewVirtualMachine(quads []shared.Quadruple, memManager *MemoryManager) *Vir
 		returnPointer:      shared.NewStack(),
 		functionStack:      shared.NewStack(),
 		Functions:          make(map[string]shared.FunctionInfo),
+		Output:             os.Stdout,
 	}
 
 	return vm
 }
 
 func (vm *VirtualMachine) Execute() error {
+	return vm.ExecuteContext(context.Background())
+}
+
+// ExecuteContext runs the program until it ends or ctx is done.
+func (vm *VirtualMachine) ExecuteContext(ctx context.Context) error {
 	//fmt.Println("These are the quads", vm.quads)
 	// fmt.Println("These are the functions", vm.Functions)
-	// vm.memoryManager.InitializeMemory()
 	vm.memoryManager.InitializeMemory()
-	for vm.instructionPointer < len(vm.quads) {
+
+	execute := vm.executeQuadruple
+	if vm.Trace != nil {
+		vm.Trace.begin(vm.memoryManager)
+		defer vm.Trace.end(vm.memoryManager)
+		execute = vm.traced(execute)
+	}
+	if vm.Profile != nil {
+		vm.Profile.begin()
+		defer vm.Profile.end()
+		execute = vm.profiled(execute)
+	}
+
+	for steps := 0; vm.instructionPointer < len(vm.quads); steps++ {
+		if steps%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return fmt.Errorf("execution stopped at instruction %d: %w", vm.instructionPointer, err)
+			}
+		}
+
 		quad := vm.quads[vm.instructionPointer]
 
-		if err := vm.executeQuadruple(quad); err != nil {
+		if err := execute(quad); err != nil {
 			return fmt.Errorf("error at instruction %d: %v", vm.instructionPointer, err)
 		}
 
@@ -45,13 +83,25 @@ func (vm *VirtualMachine) Execute() error {
 	return nil
 }
 
+// profiled wraps execute so it records each quad in the profile. The
+// function a gosub enters is on top of the function stack since its era.
+func (vm *VirtualMachine) profiled(execute func(shared.Quadruple) error) func(shared.Quadruple) error {
+	return func(quad shared.Quadruple) error {
+		callee, _ := vm.functionStack.Top().(string)
+		err := execute(quad)
+		vm.Profile.record(quad, callee)
+		return err
+	}
+}
+
 func (vm *VirtualMachine) executeQuadruple(quad shared.Quadruple) error {
 	switch quad.Operator {
-	case "+", "-", "*", "/":
-		// fmt.Println("Entering with", quad.Operator)
+	case "+", "-", "*", "/", "%", "**":
 		return vm.executeArithmetic(quad)
 	case "=":
 		return vm.executeAssignment(quad)
+	case "int", "float", "round", "floor", "ceil":
+		return vm.executeConversion(quad)
 	case "<", ">", "==", "!=":
 		return vm.executeComparison(quad)
 	case "print":
@@ -60,14 +110,24 @@ func (vm *VirtualMachine) executeQuadruple(quad shared.Quadruple) error {
 		return vm.executeGoto(quad)
 	case "gotof":
 		return vm.executeGotoF(quad)
+	case "jumptable":
+		return vm.executeJumpTable(quad)
 	case "gosub":
 		return vm.executeGosub(quad)
 	case "era":
 		return vm.executeEra(quad)
 	case "endproc":
 		return vm.executeEndproc(quad)
+	case "frame":
+		return vm.executeFrame(quad)
 	case "param":
 		return vm.executeParam(quad)
+	case "paramref":
+		return vm.executeParamRef(quad)
+	case "callbuiltin":
+		return vm.executeCallBuiltin(quad)
+	case "callnative":
+		return vm.executeCallNative(quad)
 	}
 
 	return nil
@@ -85,52 +145,9 @@ func (vm *VirtualMachine) executeArithmetic(quad shared.Quadruple) error {
 		return fmt.Errorf("failed to load right operand: %v", err)
 	}
 
-	var result interface{}
-	var leftFloat, rightFloat float64
-	isFloatOperation := false
-
-	switch l := leftVal.(type) {
-	case int:
-		leftFloat = float64(l)
-	case float64:
-		leftFloat = l
-		isFloatOperation = true
-	default:
-		return fmt.Errorf("invalid left operand type: %T", leftVal)
-	}
-
-	switch r := rightVal.(type) {
-	case int:
-		rightFloat = float64(r)
-	case float64:
-		rightFloat = r
-		isFloatOperation = true
-	default:
-		return fmt.Errorf("invalid right operand type: %T", rightVal)
-	}
-
-	var floatResult float64
-	switch quad.Operator {
-	case "+":
-		floatResult = leftFloat + rightFloat
-	case "-":
-		floatResult = leftFloat - rightFloat
-	case "*":
-		floatResult = leftFloat * rightFloat
-	case "/":
-		if rightFloat == 0 {
-			return fmt.Errorf("division by zero")
-		}
-		floatResult = leftFloat / rightFloat
-		isFloatOperation = true // Division always returns float
-	default:
-		return fmt.Errorf("unknown arithmetic operator: %s", quad.Operator)
-	}
-
-	if isFloatOperation {
-		result = floatResult
-	} else {
-		result = int(floatResult)
+	result, err := shared.Arithmetic(quad.Operator, leftVal, rightVal)
+	if err != nil {
+		return err
 	}
 
 	return vm.memoryManager.Store(quad.Result.(int), result)
@@ -145,6 +162,20 @@ func (vm *VirtualMachine) executeAssignment(quad shared.Quadruple) error {
 	return vm.memoryManager.Store(quad.Result.(int), value)
 }
 
+func (vm *VirtualMachine) executeConversion(quad shared.Quadruple) error {
+	value, err := vm.memoryManager.Load(quad.LeftOp.(int))
+	if err != nil {
+		return fmt.Errorf("failed to load operand: %v", err)
+	}
+
+	result, err := shared.Convert(quad.Operator, value)
+	if err != nil {
+		return err
+	}
+
+	return vm.memoryManager.Store(quad.Result.(int), result)
+}
+
 func (vm *VirtualMachine) executeComparison(quad shared.Quadruple) error {
 	// fmt.Println("Entering execution")
 	leftVal, err := vm.memoryManager.Load(quad.LeftOp.(int))
@@ -159,47 +190,60 @@ func (vm *VirtualMachine) executeComparison(quad shared.Quadruple) error {
 	}
 	// fmt.Println("This is the rightVal", rightVal)
 
-	var leftFloat, rightFloat float64
+	// Ints are compared natively so large values do not lose precision
+	var order int
+	leftInt, leftIsInt := leftVal.(int)
+	rightInt, rightIsInt := rightVal.(int)
+	if leftIsInt && rightIsInt {
+		order = cmp.Compare(leftInt, rightInt)
+	} else {
+		var leftFloat, rightFloat float64
+
+		switch v := leftVal.(type) {
+		case int:
+			leftFloat = float64(v)
+		case float64:
+			leftFloat = v
+		default:
+			return fmt.Errorf("invalid type for comparison: %T", leftVal)
+		}
 
-	switch v := leftVal.(type) {
-	case int:
-		leftFloat = float64(v)
-	case float64:
-		leftFloat = v
-	default:
-		return fmt.Errorf("invalid type for comparison: %T", leftVal)
-	}
+		switch v := rightVal.(type) {
+		case int:
+			rightFloat = float64(v)
+		case float64:
+			rightFloat = v
+		default:
+			return fmt.Errorf("invalid type for comparison: %T", rightVal)
+		}
 
-	switch v := rightVal.(type) {
-	case int:
-		rightFloat = float64(v)
-	case float64:
-		rightFloat = v
-	default:
-		return fmt.Errorf("invalid type for comparison: %T", rightVal)
+		if leftFloat != leftFloat || rightFloat != rightFloat {
+			return vm.memoryManager.Store(quad.Result.(int), boolToInt(quad.Operator == "!="))
+		}
+		order = cmp.Compare(leftFloat, rightFloat)
 	}
 
 	var result bool
-	var intResult int
 
 	switch quad.Operator {
 	case "<":
-		result = leftFloat < rightFloat
+		result = order < 0
 	case ">":
-		result = leftFloat > rightFloat
+		result = order > 0
 	case "==":
-		result = leftFloat == rightFloat
+		result = order == 0
 	case "!=":
-		result = leftFloat != rightFloat
+		result = order != 0
 	}
 
-	if result {
-		intResult = 1
-	} else {
-		intResult = 0
+	return vm.memoryManager.Store(quad.Result.(int), boolToInt(result))
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
 	}
-	// fmt.Println("This is where we store", quad.Result)
-	return vm.memoryManager.Store(quad.Result.(int), intResult)
+	return 0
 }
 
 func (vm *VirtualMachine) executePrint(quad shared.Quadruple) error {
@@ -213,17 +257,17 @@ func (vm *VirtualMachine) executePrint(quad shared.Quadruple) error {
 		switch v := value.(type) {
 		case string:
 			cleanStr := strings.Trim(v, "\"")
-			fmt.Print(cleanStr, " ")
+			fmt.Fprint(vm.Output, cleanStr, " ")
 		case int:
-			fmt.Print(v, " ")
+			fmt.Fprint(vm.Output, v, " ")
 		case float64:
-			fmt.Printf("%.2f ", v)
+			fmt.Fprintf(vm.Output, "%.2f ", v)
 		default:
 			return fmt.Errorf("unsupported type for printing: %T", value)
 		}
 
 		if i == len(items)-1 {
-			fmt.Println()
+			fmt.Fprintln(vm.Output)
 		}
 	}
 	return nil
@@ -247,35 +291,112 @@ func (vm *VirtualMachine) executeGotoF(quad shared.Quadruple) error {
 	return nil
 }
 
+func (vm *VirtualMachine) executeJumpTable(quad shared.Quadruple) error {
+	selector, err := vm.memoryManager.Load(quad.LeftOp.(int))
+	if err != nil {
+		return err
+	}
+
+	value, ok := selector.(int)
+	if !ok {
+		return fmt.Errorf("invalid type for switch selector: %T", selector)
+	}
+
+	targets := quad.Result.([]int)
+	index := value - quad.RightOp.(int)
+	if index >= 0 && index < len(targets) {
+		vm.instructionPointer = targets[index] - 1
+	}
+
+	return nil
+}
+
+// executeFrame creates the activation record of the main section.
+func (vm *VirtualMachine) executeFrame(quad shared.Quadruple) error {
+	vm.memoryManager.PushNewFunctionSegment(false, quad.LeftOp.(int), quad.RightOp.(int))
+	return nil
+}
+
 func (vm *VirtualMachine) executeParam(quad shared.Quadruple) error {
-	valueAddr := quad.LeftOp.(int)
+	value, err := vm.memoryManager.Load(quad.LeftOp.(int))
+	if err != nil {
+		return err
+	}
 
-	if err := vm.memoryManager.PopFunctionSegment(); err != nil {
+	address, err := vm.parameterAddress(quad.RightOp.(int))
+	if err != nil {
 		return err
 	}
-	currentFunction := vm.functionStack.Pop()
+	return vm.memoryManager.StoreParam(address, value)
+}
 
-	value, err := vm.memoryManager.Load(valueAddr)
+// executeParamRef passes the variable at LeftOp by reference, the parameter
+// refers to it instead of holding a copy of its value.
+func (vm *VirtualMachine) executeParamRef(quad shared.Quadruple) error {
+	address, err := vm.parameterAddress(quad.RightOp.(int))
 	if err != nil {
 		return err
 	}
+	return vm.memoryManager.StoreParamRef(address, quad.LeftOp.(int))
+}
+
+// parameterAddress returns the local address of the parameter slot index of
+// the function being called.
+func (vm *VirtualMachine) parameterAddress(index int) (int, error) {
+	functionName, _ := vm.functionStack.Top().(string)
+	function, exists := vm.Functions[functionName]
+	if !exists {
+		return -1, fmt.Errorf("function does not exist")
+	}
 
-	vm.functionStack.Push(currentFunction)
-	function, exists := vm.Functions[currentFunction.(string)]
-	if exists {
-		vm.memoryManager.PushNewFunctionSegment(false, function.IntVarsCount, function.FloatVarsCount)
-	} else {
-		return fmt.Errorf("function does not exist")
+	if index >= len(function.Parameters) {
+		return -1, fmt.Errorf("function '%s' has no parameter %d", functionName, index)
+	}
+	return function.Parameters[index].Address, nil
+}
+
+func (vm *VirtualMachine) executeCallBuiltin(quad shared.Quadruple) error {
+	name := quad.LeftOp.(string)
+	builtin, exists := builtins.Lookup(name)
+	if !exists {
+		return fmt.Errorf("builtin '%s' is not registered", name)
+	}
+
+	return vm.executeHostCall(quad, builtin.Call)
+}
+
+func (vm *VirtualMachine) executeCallNative(quad shared.Quadruple) error {
+	name := quad.LeftOp.(string)
+	function, exists := vm.Natives.Lookup(name)
+	if !exists {
+		return fmt.Errorf("native function '%s' is not registered", name)
 	}
 
-	index := quad.RightOp.(int)
-	currParam := function.Parameters[index]
-	currParamAddr := currParam.Address
-	if err := vm.memoryManager.Store(currParamAddr, value); err != nil {
+	return vm.executeHostCall(quad, function.Call)
+}
+
+// executeHostCall loads the arguments of a builtin or native call, runs it
+// and stores its result.
+func (vm *VirtualMachine) executeHostCall(quad shared.Quadruple, call func([]interface{}) (interface{}, error)) error {
+	addresses := quad.RightOp.([]int)
+	args := make([]interface{}, len(addresses))
+	for i, addr := range addresses {
+		value, err := vm.memoryManager.Load(addr)
+		if err != nil {
+			return fmt.Errorf("failed to load argument %d of '%s': %v", i, quad.LeftOp, err)
+		}
+		args[i] = value
+	}
+
+	result, err := call(args)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	if quad.Result == nil {
+		return nil
+	}
+	return vm.memoryManager.Store(quad.Result.(int), result)
 }
 
 func (vm *VirtualMachine) executeEra(quad shared.Quadruple) error {
@@ -285,7 +406,7 @@ func (vm *VirtualMachine) executeEra(quad shared.Quadruple) error {
 	//fmt.Println("This is the functionInfo", functionInfo)
 	//fmt.Println("These are the counts", functionInfo.IntVarsCount, functionInfo.FloatVarsCount)
 	if exists {
-		vm.memoryManager.PushNewFunctionSegment(false, functionInfo.IntVarsCount, functionInfo.FloatVarsCount)
+		vm.memoryManager.PrepareFunctionSegment(functionInfo.IntVarsCount, functionInfo.FloatVarsCount)
 	} else {
 		return fmt.Errorf("function does not exist")
 	}
@@ -303,6 +424,9 @@ func (vm *VirtualMachine) executeEndproc(quad shared.Quadruple) error {
 
 func (vm *VirtualMachine) executeGosub(quad shared.Quadruple) error {
 	start := quad.Result
+	if err := vm.memoryManager.PushPreparedSegment(); err != nil {
+		return err
+	}
 	vm.returnPointer.Push(vm.instructionPointer)
 	vm.instructionPointer = start.(int) - 1
 	return nil
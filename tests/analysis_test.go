@@ -0,0 +1,173 @@
+package tests
+
+import (
+	"bytes"
+	"pogo/src/analysis"
+	"pogo/src/lexer"
+	"pogo/src/parser"
+	"pogo/src/shared"
+	"pogo/src/virtualmachine"
+	"strings"
+	"testing"
+)
+
+func checkAssignments(t *testing.T, src string, strict bool) []shared.Diagnostic {
+	t.Helper()
+
+	p := parser.NewParser(lexer.NewLexer([]byte(src)))
+	if err := p.ParseProgram(); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	return analysis.CheckDefiniteAssignment(p.CodeGenerator.Quads, p.SymbolTable, strict)
+}
+
+func TestDefiniteAssignment(t *testing.T) {
+	const src = `program flow;
+var a, b, c, d, g : int;
+
+func setG() {
+    g = 1;
+};
+
+func locals(n : int) {
+    var m : int;
+    if (n > 0) {
+        m = n;
+    }
+    print(m)
+};
+
+begin
+    print(a)
+    if (1 > 0) {
+        b = 1;
+        c = 1;
+    } else {
+        b = 2;
+    }
+    print(b, c)
+    while (b < 3) {
+        d = b;
+        b = b + 1;
+    }
+    print(d)
+    setG()
+    print(g)
+    locals(b)
+end
+`
+	diagnostics := checkAssignments(t, src, false)
+
+	want := []struct {
+		line int
+		msg  string
+	}{
+		{13, "variable 'm' may be used before being assigned"},
+		{17, "variable 'a' may be used before being assigned"},
+		{24, "variable 'c' may be used before being assigned"},
+		{29, "variable 'd' may be used before being assigned"},
+	}
+
+	if len(diagnostics) != len(want) {
+		t.Fatalf("expected %d diagnostics, got %v", len(want), diagnostics)
+	}
+	for i, w := range want {
+		d := diagnostics[i]
+		if d.Line != w.line || d.Message != w.msg || d.Severity != shared.SeverityWarning {
+			t.Errorf("diagnostic %d: expected line %d %q, got %v", i, w.line, w.msg, d)
+		}
+	}
+}
+
+func TestDefiniteAssignmentStrict(t *testing.T) {
+	const src = `program strict;
+var x : int;
+begin
+    print(x)
+end
+`
+	diagnostics := checkAssignments(t, src, true)
+	if len(diagnostics) != 1 || diagnostics[0].Severity != shared.SeverityError {
+		t.Fatalf("expected one error, got %v", diagnostics)
+	}
+}
+
+func TestDefiniteAssignmentOutParameter(t *testing.T) {
+	const src = `program out;
+var r, u : int;
+
+func set(ref out : int) {
+    out = 7;
+};
+
+func bump(ref n : int) {
+    n = n + 1;
+};
+
+begin
+    set(r)
+    print(r)
+    bump(u)
+end
+`
+	// Passing r to set needs no value and assigns it, the n bump reads is
+	// unassigned because u is
+	diagnostics := checkAssignments(t, src, true)
+	if len(diagnostics) != 1 || diagnostics[0].Line != 9 || diagnostics[0].Severity != shared.SeverityError ||
+		diagnostics[0].Message != "variable 'n' may be used before being assigned" {
+		t.Fatalf("expected one error for n, got %v", diagnostics)
+	}
+
+	if got := runPogo(t, strings.Replace(src, "bump(u)", "", 1)); got != "7 \n" {
+		t.Fatalf("expected %q, got %q", "7 \n", got)
+	}
+}
+
+func TestControlFlowGraphDot(t *testing.T) {
+	const src = `program graph;
+var n : int;
+func countdown(k : int) {
+    while (k > 0) {
+        k = k - 1;
+    }
+};
+begin
+    n = 3;
+    countdown(n)
+end`
+
+	p := parser.NewParser(lexer.NewLexer([]byte(src)))
+	if err := p.ParseProgram(); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	graph := analysis.BuildControlFlowGraph(p.CodeGenerator.Quads, analysis.FunctionStarts(p.SymbolTable.GetFunctionInfos()))
+	entry := graph.BlockOf(p.SymbolTable.GetFunctionInfos()["countdown"].StartQuad)
+	if entry.Function != "countdown" || entry != graph.Blocks[graph.Entries["countdown"]] {
+		t.Fatalf("expected a block starting countdown, got %+v", entry)
+	}
+	if body := graph.Blocks[0]; body.Function != virtualmachine.ProgramBody {
+		t.Fatalf("expected the program body named like in profiles, got %q", body.Function)
+	}
+
+	var out bytes.Buffer
+	names := analysis.NewAddressNames(p.SymbolTable, p.CodeGenerator.MemoryManager)
+	if err := analysis.WriteDot(&out, graph, names); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dot := out.String()
+	for _, expected := range []string{
+		"digraph cfg {",
+		`label="<program>";`,
+		`label="countdown";`,
+		`n = 3\l`,
+		`t1 = k - 1\l`,
+		`[label="false"];`,
+		`[style=dashed, label="call"];`,
+	} {
+		if !strings.Contains(dot, expected) {
+			t.Fatalf("expected %q in\n%s", expected, dot)
+		}
+	}
+}
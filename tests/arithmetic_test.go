@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestIntegerArithmetic(t *testing.T) {
+	const src = `
+program arith;
+var a, b, big : int;
+var f : float;
+
+begin
+    a = 7;
+    b = 2;
+    print(a / b, -7 / 2, a % b, -7 % 2, a ** b, 2 ** 3 ** 2)
+    print(10 - 4 - 3, 100 / 10 / 5, 2 * 3 ** 2)
+    big = 2 ** 62 + 1;
+    print(big, big - 1 == 2 ** 62)
+    f = a / b;
+    print(f, a / 2.0, 7.5 % 2, 2.0 ** -1)
+end
+`
+	got := runPogo(t, src)
+	want := "3 -3 1 -1 49 512 \n3 2 18 \n4611686018427387905 1 \n3.00 3.50 1.50 0.50 \n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestModuloByZero(t *testing.T) {
+	const src = `
+program arith;
+var a : int;
+
+begin
+    a = 0;
+    print(5 % a)
+end
+`
+	err := runtimeError(t, src)
+	if !strings.Contains(err.Error(), "modulo by zero") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestIntegerOverflow(t *testing.T) {
+	const src = `
+program arith;
+var a, b, max, min : int;
+
+begin
+    a = 2;
+    b = 63;
+    max = 9223372036854775807;
+    min = -9223372036854775808;
+    print(a ** 62, -a ** b, max - 1 + 1, min + max, min / 1)
+    print(%s)
+end
+`
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"max + 1", "integer overflow in 9223372036854775807 + 1"},
+		{"min - a", "integer overflow in -9223372036854775808 - 2"},
+		{"max * a", "integer overflow in 9223372036854775807 * 2"},
+		{"min / -1", "integer overflow in -9223372036854775808 / -1"},
+		{"-min", "integer overflow in -9223372036854775808 * -1"},
+		{"a ** b", "integer overflow in 2 ** 63"},
+	}
+
+	for _, tt := range tests {
+		out, err := execute(t, fmt.Sprintf(src, tt.expr))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("%s: unexpected error: %v", tt.expr, err)
+		}
+		if want := "4611686018427387904 -9223372036854775808 9223372036854775807 -1 -9223372036854775808 \n"; out != want {
+			t.Fatalf("%s: expected %q, got %q", tt.expr, want, out)
+		}
+	}
+
+	err := compileError(t, "program arith;\nconst big : int = 3 ** 40;\nbegin\n    print(big)\nend\n")
+	if !strings.Contains(err.Error(), "integer overflow in 3 ** 40") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = compileError(t, "program arith;\nvar n : int;\nbegin\n    n = 99999999999999999999;\nend\n")
+	if err.Error() != "line 4: int literal '99999999999999999999' out of range" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"pogo/src/ast"
+	"pogo/src/parser"
+	"strings"
+	"testing"
+)
+
+func TestParseTree(t *testing.T) {
+	const src = `program shapes;
+var n : int = 2 + 3 * 4;
+func f(ref x : int) {
+    x = -x ** 2;
+};
+begin
+    if (n > 1) { f(n) } else if (n < 0) { print("neg") }
+end`
+
+	program, err := parser.Parse([]byte(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if program.Name.Name != "shapes" || program.Module || len(program.Funcs) != 1 || program.Main == nil {
+		t.Fatalf("unexpected program %+v", program)
+	}
+
+	// Precedence is in the shape of the tree: 2 + (3 * 4)
+	value := program.Decls[0].(*ast.VarDecl).Groups[0].Value.(*ast.BinaryExpr)
+	if value.Op != "+" || value.Y.(*ast.BinaryExpr).Op != "*" {
+		t.Fatalf("unexpected initializer %+v", value)
+	}
+	if value.Pos != (ast.Pos{Line: 2, Column: 17}) {
+		t.Fatalf("expected the operator at 2:17, got %+v", value.Pos)
+	}
+
+	param := program.Funcs[0].Params[0]
+	if !param.Ref || param.Name.Name != "x" || param.Type.Name != "int" {
+		t.Fatalf("unexpected parameter %+v", param)
+	}
+
+	ifStmt := program.Main.Stmts[0].(*ast.IfStmt)
+	if _, ok := ifStmt.Else.(*ast.IfStmt); !ok {
+		t.Fatalf("expected an else if, got %T", ifStmt.Else)
+	}
+
+	names := make([]string, 0)
+	ast.Inspect(program, func(node ast.Node) bool {
+		if name, ok := node.(*ast.Name); ok {
+			names = append(names, name.Name)
+		}
+		return true
+	})
+	if got := strings.Join(names, " "); got != "x x n n n" {
+		t.Fatalf("expected the names in source order, got %q", got)
+	}
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	_, err := parser.Parse([]byte("program p;\nbegin\n    x = ;\nend"))
+	if err == nil || !strings.Contains(err.Error(), "unexpected token in factor") {
+		t.Fatalf("expected a syntax error, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"pogo/src/builtins"
+	"pogo/src/shared"
+	"strings"
+	"testing"
+)
+
+func TestMathBuiltins(t *testing.T) {
+	const src = `
+program maths;
+var n : int;
+var x : float;
+
+begin
+    n = -7;
+    x = sqrt(16) + pow(2, 3);
+    print(x, abs(n), abs(-2.5), min(n, 3), max(n, 3), max(1, 2.5))
+    print(-sqrt(4.0), sin(0.0), cos(0.0), round(pow(10, 2)) + 1)
+end
+`
+	got := runPogo(t, src)
+	want := "12.00 7 2.50 -7 3 2.50 \n-2.00 0.00 1.00 101 \n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRegisteredBuiltin(t *testing.T) {
+	var recorded []interface{}
+	builtins.MustRegister(builtins.Builtin{
+		Name:       "record",
+		Signatures: []builtins.Signature{{Parameters: []shared.Type{shared.TypeInt}, Result: shared.TypeVoid}},
+		Impl: func(args []interface{}) (interface{}, error) {
+			recorded = append(recorded, args[0])
+			return nil, nil
+		},
+	})
+	t.Cleanup(func() { builtins.Unregister("record") })
+
+	const src = `
+program hooks;
+var i : int;
+
+begin
+    i = 0;
+    while (i < 3) {
+        record(i * 10)
+        i = i + 1;
+    }
+end
+`
+	runPogo(t, src)
+	if len(recorded) != 3 || recorded[2] != 20 {
+		t.Fatalf("unexpected recorded values: %v", recorded)
+	}
+
+	err := compileError(t, "program hooks;\nvar x : int;\nbegin\n    x = record(1);\nend\n")
+	if !strings.Contains(err.Error(), "does not return a value") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBuiltinErrors(t *testing.T) {
+	err := compileError(t, "program b;\nvar x : float;\nbegin\n    x = sqrt(1.0, 2.0);\nend\n")
+	if !strings.Contains(err.Error(), "no signature of builtin 'sqrt'") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = runtimeError(t, "program b;\nvar x : float;\nbegin\n    x = sqrt(-1.0);\nend\n")
+	if !strings.Contains(err.Error(), "square root of negative number") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package tests
+
+import "testing"
+
+func TestConstDeclarations(t *testing.T) {
+	const src = `
+program consts;
+const SIZE : int = 4 * (2 + 1), HALF : float = SIZE / 2;
+var x : int;
+
+func show() {
+    const OFFSET : int = -SIZE + 2;
+    print(OFFSET, HALF)
+};
+
+begin
+    show()
+    x = SIZE;
+    switch (x) {
+        case SIZE - 1:
+            print(1)
+        case SIZE:
+            print(2)
+    }
+end
+`
+	got := runPogo(t, src)
+	want := "-10 6.00 \n2 \n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestConstErrors(t *testing.T) {
+	tests := []struct{ src, want string }{
+		{"program c;\nconst A : int = 1;\nbegin\n    A = 2;\nend\n", "line 4: cannot assign to constant 'A'"},
+		{"program c;\nvar x : int;\nconst A : int = x;\nbegin\nend\n", "line 3: 'x' is not a constant, constant expressions can only use literals and constants"},
+		{"program c;\nconst A : int = 1 / 0;\nbegin\nend\n", "line 2: division by zero in constant expression"},
+	}
+	for _, tt := range tests {
+		if err := compileError(t, tt.src); err.Error() != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, err)
+		}
+	}
+}
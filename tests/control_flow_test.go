@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"fmt"
+	"pogo/src/lexer"
+	"pogo/src/parser"
+	"strings"
+	"testing"
+)
+
+func TestElseIfChain(t *testing.T) {
+	const src = `
+program chain;
+var x : int;
+
+begin
+    x = 0;
+    while (x < 4) {
+        if (x == 0) {
+            print("zero")
+        } else if (x == 1) {
+            print("one")
+        } else if (x == 2) {
+            print("two")
+        } else {
+            print("many")
+        }
+        x = x + 1;
+    }
+end
+`
+	got := runPogo(t, src)
+	want := "zero \none \ntwo \nmany \n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSwitch(t *testing.T) {
+	tests := []struct {
+		name      string
+		cases     string
+		jumptable bool
+		want      []string
+	}{
+		// Sparse values compile to comparisons, dense ones to a jump table.
+		{"comparisons", "case 1: print(\"a\") case 10, 20: print(\"b\") default: print(\"other\")", false,
+			[]string{"other", "a", "other", "other", "other", "other", "other"}},
+		{"jumptable", "case 1: print(\"a\") case 2, 3: print(\"b\") case 4: print(\"c\") case 6: print(\"d\") default: print(\"other\")", true,
+			[]string{"other", "a", "b", "b", "c", "other", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := fmt.Sprintf(`
+program sw;
+var x : int;
+
+begin
+    x = 0;
+    while (x < 7) {
+        switch (x) {
+            %s
+        }
+        x = x + 1;
+    }
+end
+`, tt.cases)
+			p := parser.NewParser(lexer.NewLexer([]byte(src)))
+			if err := p.ParseProgram(); err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			jumptable := false
+			for _, quad := range p.CodeGenerator.Quads {
+				jumptable = jumptable || quad.Operator == "jumptable"
+			}
+			if jumptable != tt.jumptable {
+				t.Fatalf("expected a jumptable quad: %v, got one: %v", tt.jumptable, jumptable)
+			}
+
+			got := strings.Fields(runPogo(t, src))
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSwitchDuplicateCase(t *testing.T) {
+	const src = `
+program sw;
+var x : int;
+
+begin
+    x = 1;
+    switch (x) {
+        case 1: x = 2;
+        case 1: x = 3;
+    }
+end
+`
+	err := compileError(t, src)
+	if !strings.Contains(err.Error(), "duplicate case value 1") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"pogo/src/semantic"
+	"pogo/src/shared"
+	"strings"
+	"testing"
+)
+
+func TestConversions(t *testing.T) {
+	const src = `
+program casts;
+const HALF : int = int(7.9) / 2;
+var n, ceiling : int;
+var x : float;
+
+begin
+    x = -2.5;
+    n = int(x);
+    ceiling = 3;
+    print(n, float(n) / 2, int(2.0 * 3.7), -int(x))
+    print(round(x), round(2.5), floor(x), ceil(x), ceil(x + 5))
+    print(ceiling, HALF, float(7) / 2)
+end
+`
+	got := runPogo(t, src)
+	want := "-2 -1.00 7 2 \n-3 3 -3 -2 3 \n3 3 3.50 \n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestConversionShadowedByVariable(t *testing.T) {
+	const src = `
+program casts;
+var round : int;
+
+begin
+    round = 4;
+    print(round * 2)
+end
+`
+	got := runPogo(t, src)
+	if got != "8 \n" {
+		t.Fatalf("expected %q, got %q", "8 \n", got)
+	}
+}
+
+func TestConversionErrors(t *testing.T) {
+	tests := []struct {
+		call string
+		want string
+	}{
+		{`round("text")`, "unexpected token in factor"},
+		{"int()", "line 5: int expects one argument, got 0"},
+		{"floor(1.5, 2)", "line 5: floor expects one argument, got 2"},
+	}
+
+	for _, tt := range tests {
+		src := "program casts;\nvar n : int;\n\nbegin\n    n = " + tt.call + ";\nend\n"
+		err := compileError(t, src)
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("%s: unexpected error: %v", tt.call, err)
+		}
+	}
+
+	// The parser never lets a string reach a conversion, the code generator
+	// still refuses one
+	ql := semantic.NewQuadrupleList()
+	ql.OperandStack.Push(14000)
+	ql.TypeStack.Push(shared.TypeString)
+	err := ql.HandleConversion("ceil")
+	if err == nil || err.Error() != "cannot apply ceil to value of type string" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVariableInitializers(t *testing.T) {
+	const src = `
+program inits;
+var x : int = 5, y : float = x * 2.0;
+var a, b : int = 3;
+
+func show() {
+    var local : float = y + a;
+    print(x, y, a, b, local)
+};
+
+begin
+    show()
+    while (a > 0) {
+        var step : int = a - 1;
+        a = step;
+    }
+    print(a)
+end
+`
+	got := runPogo(t, src)
+	want := "5 10.00 3 3 13.00 \n0 \n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestVariableInitializerTypeError(t *testing.T) {
+	const src = `
+program inits;
+var x : int = 2.5;
+
+begin
+    print(x)
+end
+`
+	err := compileError(t, src)
+	if !strings.Contains(err.Error(), "cannot assign value of type float to variable of type int") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"encoding/json"
+	"pogo/src/dump"
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	const src = `program dumped;
+const K : int = 3;
+type P struct { x : float; }
+var n : int;
+func f(ref p : P) {
+    p.x = 1.5;
+};
+begin
+    var q : P;
+    n = K;
+    f(q)
+    print("n", n)
+end`
+
+	doc, err := dump.Source([]byte(src), "dumped.pogo", dump.Sections{AST: true, Symbols: true, Quads: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if doc.AST["kind"] != "Program" || doc.AST["name"].(dump.Node)["name"] != "dumped" {
+		t.Fatalf("unexpected tree %v", doc.AST)
+	}
+
+	symbols := doc.Symbols
+	if symbols.Scopes[0].Name != "global" {
+		t.Fatalf("expected the global scope first, got %s", symbols.Scopes[0].Name)
+	}
+	names := make([]string, 0)
+	for _, symbol := range symbols.Scopes[0].Symbols {
+		names = append(names, symbol.Kind+" "+symbol.Name)
+	}
+	if got := strings.Join(names, ", "); got != "constant K, variable n" {
+		t.Fatalf("unexpected global symbols %q", got)
+	}
+
+	if len(symbols.Functions) != 1 || symbols.Functions[0].StartQuad < 0 {
+		t.Fatalf("unexpected functions %+v", symbols.Functions)
+	}
+	param := symbols.Functions[0].Parameters[0]
+	if param.Kind != "ref" || param.Type != "P" || param.Address != nil || len(param.Fields) != 1 {
+		t.Fatalf("unexpected parameter %+v", param)
+	}
+	if len(symbols.Structs) != 1 || symbols.Structs[0].Fields[0].Type != "float" {
+		t.Fatalf("unexpected structs %+v", symbols.Structs)
+	}
+
+	constants := make([]string, 0)
+	for _, constant := range symbols.Constants {
+		constants = append(constants, constant.Type)
+	}
+	if got := strings.Join(constants, " "); got != "int float string" {
+		t.Fatalf("expected the constants by address, got %q", got)
+	}
+
+	if got := symbols.Constants[2].Value; got != "n" {
+		t.Fatalf("expected the string constant without quotes, got %q", got)
+	}
+
+	if doc.Quads[0].Op != "goto" || doc.Quads[len(doc.Quads)-1].Op != "print" {
+		t.Fatalf("unexpected quads %+v", doc.Quads)
+	}
+
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, key := range []string{`"version":1`, `"startQuad"`, `"op":"gosub"`, `"kind":"CallStmt"`} {
+		if !strings.Contains(string(encoded), key) {
+			t.Errorf("expected %s in %s", key, encoded)
+		}
+	}
+}
+
+func TestDumpTree(t *testing.T) {
+	const src = `program tree;
+var i : int;
+begin
+    i = -(1 + 2);
+    if (i > 0) { print("a\tb") } else { i = 0; }
+    while (i < 3) { i = i + 1; }
+    switch (i) {
+        case 3: print(` + "`raw`" + `)
+        default: print(i)
+    }
+end`
+
+	doc, err := dump.Source([]byte(src), "tree.pogo", dump.Sections{AST: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var strs []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch x := v.(type) {
+		case dump.Node:
+			for key, field := range x {
+				if key != strings.ToLower(key[:1])+key[1:] {
+					t.Errorf("%s: field %q is not named by a json tag", x["kind"], key)
+				}
+				walk(field)
+			}
+			if x["kind"] == "BasicLit" && x["type"] == "string" {
+				strs = append(strs, x["value"].(string))
+			}
+		case []interface{}:
+			for _, item := range x {
+				walk(item)
+			}
+		}
+	}
+	walk(doc.AST)
+
+	if got := strings.Join(strs, "|"); got != "a\tb|raw" {
+		t.Errorf("expected the string literals without quotes, got %q", got)
+	}
+}
+
+func TestDumpSections(t *testing.T) {
+	doc, err := dump.Source([]byte("program p;\nbegin\n    print(1)\nend"), "p.pogo", dump.Sections{Quads: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	encoded, _ := json.Marshal(doc)
+	if strings.Contains(string(encoded), `"ast"`) || strings.Contains(string(encoded), `"symbols"`) {
+		t.Fatalf("expected only the quads, got %s", encoded)
+	}
+}
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"pogo"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCompileAndRun(t *testing.T) {
+	const src = `
+program embedded;
+var total, i : int;
+
+begin
+    total = 0;
+    i = 1;
+    while (i < 5) {
+        total = total + i;
+        i = i + 1;
+    }
+    print("total", total)
+end
+`
+	program, err := pogo.Compile([]byte(src), pogo.CompileOptions{})
+	if err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+
+	// Every run starts from fresh memory, also when runs overlap
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var out bytes.Buffer
+			if err := program.Run(context.Background(), pogo.RunOptions{Output: &out}); err != nil {
+				t.Errorf("run error: %v", err)
+			}
+			if out.String() != "total 10 \n" {
+				t.Errorf("unexpected output %q", out.String())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRunCancellation(t *testing.T) {
+	const src = `
+program forever;
+var i : int;
+
+begin
+    i = 0;
+    while (0 < 1) {
+        i = i + 1;
+    }
+end
+`
+	program, err := pogo.Compile([]byte(src), pogo.CompileOptions{})
+	if err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = program.Run(ctx, pogo.RunOptions{Output: &bytes.Buffer{}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestCompileStrict(t *testing.T) {
+	const src = `
+program unassigned;
+var x : int;
+
+begin
+    print(x)
+end
+`
+	program, err := pogo.Compile([]byte(src), pogo.CompileOptions{})
+	if err != nil || len(program.Diagnostics) != 1 {
+		t.Fatalf("expected one warning, got %v, %v", program, err)
+	}
+
+	_, err = pogo.Compile([]byte(src), pogo.CompileOptions{Strict: true})
+	var compileErr *pogo.CompileError
+	if !errors.As(err, &compileErr) || len(compileErr.Diagnostics) != 1 {
+		t.Fatalf("expected a compile error, got %v", err)
+	}
+}
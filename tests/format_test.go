@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"pogo/src/format"
+	"pogo/src/lexer"
+	"pogo/src/token"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	const src = `program   messy ;
+// globals
+var a,b : int = 1;   var c:float;
+const K:int=-2*3;
+
+
+type P struct {x,y:float;};
+func f( ref p:P,n:int){
+if(n<2){ p.x=p.x+ -1.5; } else if (n==3) {p.y=float(n)**2;}else{
+
+  f(p,n-1)   // recurse
+}
+switch(n){case 1,2: print("a") case -3:
+b=n%2;
+default: print(n, "x")}
+};
+begin
+var q:P; q.x=1;q.y=2;
+f(q,a) print(q.x) /* done */
+end`
+
+	const want = `program messy;
+// globals
+var a, b : int = 1;
+var c : float;
+const K : int = -2 * 3;
+
+type P struct {
+    x, y : float;
+}
+func f(ref p : P, n : int) {
+    if (n < 2) {
+        p.x = p.x + -1.5;
+    } else if (n == 3) {
+        p.y = float(n) ** 2;
+    } else {
+        f(p, n - 1) // recurse
+    }
+    switch (n) {
+        case 1, 2:
+            print("a")
+        case -3:
+            b = n % 2;
+        default:
+            print(n, "x")
+    }
+};
+begin
+    var q : P;
+    q.x = 1;
+    q.y = 2;
+    f(q, a)
+    print(q.x) /* done */
+end
+`
+
+	got, err := format.Source([]byte(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+// TestFormatSubmissions checks that formatting keeps the tokens and the
+// comments of the sample programs and that it is idempotent.
+func TestFormatSubmissions(t *testing.T) {
+	files, _ := filepath.Glob("pogo_parser_tests/*.pogo")
+	if len(files) == 0 {
+		t.Fatal("no sample programs found")
+	}
+
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		formatted, err := format.Source(src)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		again, err := format.Source(formatted)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if string(again) != string(formatted) {
+			t.Errorf("%s: formatting is not idempotent, got\n%s", file, again)
+		}
+		if want, got := significantTokens(src), significantTokens(formatted); want != got {
+			t.Errorf("%s: tokens changed\nwant %s\ngot  %s", file, want, got)
+		}
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	tests := map[string]string{
+		"program p;\nbegin\n    x = 1 # 2;\nend": "line 3: invalid token '#'",
+		"program p;\nbegin\n    x = 1;\n}":       "line 4: unexpected '}'",
+		"program p;\nfunc f() {\n    x = 1;\n":   "unexpected end of file",
+	}
+	for src, want := range tests {
+		_, err := format.Source([]byte(src))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	}
+}
+
+func TestScanLossless(t *testing.T) {
+	const src = "program p; // name\n\tvar x : int; /* a\n b */\nbegin x = 1; end  "
+	tokens := lexer.NewLexer([]byte(src)).ScanLossless()
+
+	var joined strings.Builder
+	comments := 0
+	for _, tok := range tokens {
+		joined.Write(tok.Lit)
+		if tok.Type == lexer.Comment {
+			comments++
+		}
+	}
+	if joined.String() != src {
+		t.Fatalf("expected the source back, got %q", joined.String())
+	}
+	if comments != 2 {
+		t.Errorf("expected 2 comments, got %d", comments)
+	}
+	if last := tokens[len(tokens)-1]; last.Type != token.EOF {
+		t.Errorf("expected EOF last, got %s", token.TokMap.TokenString(last))
+	}
+	for _, tok := range tokens {
+		if string(tok.Lit) == "begin" && (tok.Line != 4 || tok.Column != 1) {
+			t.Errorf("expected begin at 4:1, got %d:%d", tok.Line, tok.Column)
+		}
+	}
+}
+
+// significantTokens joins the literals of the tokens and comments of src.
+func significantTokens(src []byte) string {
+	var parts []string
+	for _, tok := range lexer.NewLexer(src).ScanLossless() {
+		if tok.Type != lexer.Whitespace {
+			parts = append(parts, strings.TrimSpace(string(tok.Lit)))
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestForwardCalls(t *testing.T) {
+	const src = `
+program parity;
+
+type Point struct {
+    x, y : int;
+}
+
+var result : int;
+var p : Point;
+
+func isEven(n : int, ref even : int) {
+    if (n == 0) {
+        even = 1;
+    } else {
+        isOdd(n - 1, even)
+    }
+};
+
+func isOdd(n : int, ref odd : int) {
+    if (n == 0) {
+        odd = 0;
+    } else {
+        isEven(n - 1, odd)
+    }
+};
+
+func report(n : int) {
+    isEven(n, result)
+    show(n, p)
+};
+
+func show(n : int, pt : Point) {
+    print(n, result, pt.x + pt.y)
+};
+
+begin
+    p.x = 1;
+    p.y = 2;
+    report(7)
+    report(10)
+end
+`
+	got := runPogo(t, src)
+	want := "7 0 3 \n10 1 3 \n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestFunctionErrors(t *testing.T) {
+	// Calls are checked against functions declared later once the file is read
+	const src = `program p;
+func f() {
+    g(1, 2)
+};
+func g(n : int) {
+    print(n)
+};
+begin
+    h()
+end
+`
+	if err := compileError(t, src); err.Error() != "line 3: function 'g' expects 1 arguments but got 2" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	undefined := strings.Replace(src, "g(1, 2)", "g(1)", 1)
+	if err := compileError(t, undefined); err.Error() != "line 9: undefined function 'h'" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
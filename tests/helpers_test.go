@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"bytes"
+	"path/filepath"
+	"pogo/src/lexer"
+	"pogo/src/parser"
+	"pogo/src/storer"
+	"testing"
+)
+
+// runPogo compiles and executes src the same way cmd/pogo does and returns
+// everything the program printed.
+func runPogo(t *testing.T, src string) string {
+	t.Helper()
+
+	out, err := execute(t, src)
+	if err != nil {
+		t.Fatalf("execution error: %v", err)
+	}
+	return out
+}
+
+// runtimeError compiles and executes src and returns the error the program
+// stopped with.
+func runtimeError(t *testing.T, src string) error {
+	t.Helper()
+
+	_, err := execute(t, src)
+	if err == nil {
+		t.Fatalf("expected a runtime error")
+	}
+	return err
+}
+
+func execute(t *testing.T, src string) (string, error) {
+	t.Helper()
+
+	p := parser.NewParser(lexer.NewLexer([]byte(src)))
+	if err := p.ParseProgram(); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	binary := filepath.Join(t.TempDir(), "test.pbin")
+	if err := storer.SaveCompiledData(p.CodeGenerator.Quads, p.SymbolTable, p.CodeGenerator.MemoryManager, binary); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+
+	vm, err := storer.LoadCompiledData(binary)
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+
+	var out bytes.Buffer
+	vm.Output = &out
+	execErr := vm.Execute()
+
+	return out.String(), execErr
+}
+
+// compileError parses src and returns the error reported by the parser.
+func compileError(t *testing.T, src string) error {
+	t.Helper()
+
+	p := parser.NewParser(lexer.NewLexer([]byte(src)))
+	err := p.ParseProgram()
+	if err == nil {
+		t.Fatalf("expected a compile error")
+	}
+	return err
+}
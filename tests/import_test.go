@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"pogo"
+	"pogo/src/lexer"
+	"pogo/src/parser"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var modules = fstest.MapFS{
+	"lib/counter.pogo": {Data: []byte(`
+module counter;
+var count : int = 0;
+
+func bump(by : int) {
+    count = count + by;
+    print("count", count)
+};
+`)},
+	"lib/mathutils.pogo": {Data: []byte(`
+module mathutils;
+import "counter.pogo";
+const SCALE : float = 1.5;
+
+func square(n : int) {
+    bump(1)
+    print(n * n, n * SCALE)
+};
+`)},
+	"lib/report.pogo": {Data: []byte(`
+module report;
+import "counter.pogo";
+
+func report() {
+    bump(10)
+};
+`)},
+	"lib/stats.pogo": {Data: []byte(`
+module stats;
+import "counter.pogo";
+const SCALE : float = 1.5;
+var shown : int = 0;
+
+func show() {
+    shown = shown + 1;
+    print("stats", counter.count, counter.count * SCALE)
+};
+`)},
+	"lib/geo.pogo": {Data: []byte(`
+module geo;
+type Point struct { x, y : float; }
+var origin : Point;
+
+func shift(p : Point, dx : float) {
+    origin.x = p.x + dx;
+    origin.y = p.y;
+};
+`)},
+	"cycle/a.pogo":     {Data: []byte("module a;\nimport \"b.pogo\";\n")},
+	"cycle/b.pogo":     {Data: []byte("module b;\nimport \"a.pogo\";\n")},
+	"lib/program.pogo": {Data: []byte("program p;\nbegin\nend\n")},
+}
+
+func compileWithModules(src string) (*pogo.Program, error) {
+	return pogo.Compile([]byte(src), pogo.CompileOptions{Filename: "main.pogo", FS: modules})
+}
+
+func TestImports(t *testing.T) {
+	const src = `
+program shapes;
+import "lib/mathutils.pogo";
+import "lib/report.pogo";
+var x : int = 3;
+
+begin
+    square(x)
+    report()
+    square(4)
+end
+`
+	program, err := compileWithModules(src)
+	if err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := program.Run(context.Background(), pogo.RunOptions{Output: &out}); err != nil {
+		t.Fatalf("run error: %v", err)
+	}
+
+	// counter.pogo is linked once, so both modules share its global
+	want := "count 1 \n9 4.50 \ncount 11 \ncount 12 \n16 6.00 \n"
+	if out.String() != want {
+		t.Fatalf("expected %q, got %q", want, out.String())
+	}
+}
+
+const moduleGlobalsProgram = `
+program globals;
+import "lib/counter.pogo";
+import "lib/stats.pogo";
+const TWICE : float = stats.SCALE * 2;
+var count : int = 7;
+
+begin
+    bump(2)
+    show()
+    counter.count = counter.count * 10 + count;
+    show()
+    print(stats.shown, TWICE, -counter.count)
+end
+`
+
+// moduleGlobalsOutput is what moduleGlobalsProgram prints, counter.count is
+// the same global in the program and in stats
+const moduleGlobalsOutput = "count 2 \nstats 2 3.00 \nstats 27 40.50 \n2 3.00 -27 \n"
+
+func TestModuleGlobals(t *testing.T) {
+	program, err := compileWithModules(moduleGlobalsProgram)
+	if err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := program.Run(context.Background(), pogo.RunOptions{Output: &out}); err != nil {
+		t.Fatalf("run error: %v", err)
+	}
+	if out.String() != moduleGlobalsOutput {
+		t.Fatalf("expected %q, got %q", moduleGlobalsOutput, out.String())
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"collision", "program p;\nimport \"lib/counter.pogo\";\nvar bump : int;\nbegin\nend\n", "symbol 'bump' already declared"},
+		{"function collision", "program p;\nimport \"lib/counter.pogo\";\nfunc bump(n : int) {\n    print(n)\n};\nbegin\nend\n", "symbol 'bump' already declared"},
+		{"missing", "program p;\nimport \"lib/missing.pogo\";\nbegin\nend\n", "cannot import 'lib/missing.pogo'"},
+		{"cycle", "program p;\nimport \"cycle/a.pogo\";\nbegin\nend\n", "import cycle: cycle/a.pogo -> cycle/b.pogo -> cycle/a.pogo"},
+		{"not a module", "program p;\nimport \"lib/program.pogo\";\nbegin\nend\n", "lib/program.pogo: line 1"},
+		{"private global", "program p;\nimport \"lib/counter.pogo\";\nbegin\n    count = 1;\nend\n", "line 4: undefined variable 'count'"},
+		{"unknown module global", "program p;\nimport \"lib/counter.pogo\";\nbegin\n    counter.total = 1;\nend\n", "undefined variable 'counter.total'"},
+		{"module constant", "program p;\nimport \"lib/stats.pogo\";\nbegin\n    stats.SCALE = 1.0;\nend\n", "cannot assign to constant 'stats.SCALE'"},
+		{"module global type", "program p;\nimport \"lib/counter.pogo\";\nbegin\n    counter.count = 1.5;\nend\n", "cannot assign value of type float"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileWithModules(tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestParseProgramOrModule(t *testing.T) {
+	tests := []struct {
+		src    string
+		module bool
+	}{
+		{"program p;\nbegin\n    print(1)\nend\n", false},
+		{"module m;\nvar n : int;\n", true},
+	}
+
+	for _, tt := range tests {
+		p := parser.NewParser(lexer.NewLexer([]byte(tt.src)))
+		module, err := p.Parse()
+		if err != nil || module != tt.module {
+			t.Errorf("%q: expected module %v, got %v, %v", tt.src, tt.module, module, err)
+		}
+		if p.Tree() == nil || p.Tree().Module != tt.module {
+			t.Errorf("%q: unexpected tree %+v", tt.src, p.Tree())
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"pogo/src/lint"
+	"strings"
+	"testing"
+)
+
+const linted = `program linted;
+var x, a : int;
+func show(x : int, ref out : int, unused : int) {
+    var t : int;
+    out = x;
+};
+func never() {
+    never()
+};
+begin
+    var r : int;
+    a = 1;
+    show(a, r, 2)
+    if (5 > 7) {
+        print("never")
+    }
+    if (1 == 1.0) {
+        print(r)
+    } else {
+        print("else")
+    }
+    while (2 - 2) {
+        print("loop")
+    }
+end`
+
+func lintFindings(t *testing.T, src string, config lint.Config) string {
+	t.Helper()
+	diagnostics, err := lint.Source([]byte(src), "linted.pogo", config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	findings := make([]string, len(diagnostics))
+	for i, diagnostic := range diagnostics {
+		findings[i] = diagnostic.String()
+	}
+	return strings.Join(findings, "\n")
+}
+
+func TestLint(t *testing.T) {
+	expected := strings.Join([]string{
+		"line 2, column 5: warning: variable 'x' is never read (unused-variable)",
+		"line 3, column 11: warning: parameter 'x' shadows the global 'x' (shadowed-global)",
+		"line 3, column 35: warning: parameter 'unused' is never used (unused-parameter)",
+		"line 4, column 9: warning: variable 't' is never read (unused-variable)",
+		"line 7, column 6: warning: function 'never' is never called (unused-function)",
+		"line 15, column 9: warning: unreachable code, the condition is always false (unreachable-code)",
+		"line 20, column 9: warning: unreachable code, the condition is always true (unreachable-code)",
+		"line 23, column 9: warning: unreachable code, the condition is always false (unreachable-code)",
+	}, "\n")
+
+	if got := lintFindings(t, linted, lint.Config{}); got != expected {
+		t.Fatalf("unexpected findings:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+func TestLintAssignedVariable(t *testing.T) {
+	const src = `program assigned;
+begin
+    var w : int;
+    w = 2;
+end`
+	got := lintFindings(t, src, lint.Config{Only: []string{"unused-variable"}})
+	if got != "line 3, column 9: warning: variable 'w' is never read (unused-variable)" {
+		t.Fatalf("unexpected findings:\n%s", got)
+	}
+}
+
+func TestLintConfig(t *testing.T) {
+	got := lintFindings(t, linted, lint.Config{Only: []string{"unused-function", "shadowed-global"}, Disable: []string{"shadowed-global"}})
+	if got != "line 7, column 6: warning: function 'never' is never called (unused-function)" {
+		t.Fatalf("unexpected findings:\n%s", got)
+	}
+
+	_, err := lint.Source([]byte(linted), "linted.pogo", lint.Config{Disable: []string{"unused-everything"}})
+	if err == nil || err.Error() != "unknown lint rule 'unused-everything'" {
+		t.Fatalf("expected an unknown rule error, got %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"pogo/src/lsp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const lspURI = "file:///work/shapes.pogo"
+
+const lspSource = `program shapes;
+
+type Point struct {
+    x, y : float;
+}
+
+var origin : Point;
+var count : int = 0;
+
+func move(ref pt : Point, dx : float) {
+    pt.x = pt.x + dx;
+    count = count + 1;
+};
+
+begin
+    var step : float = 2;
+    origin.x = 1; origin.y = 0;
+    move(origin, step)
+    print(origin.x, count)
+end
+`
+
+type lspMessage struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+}
+
+// runLanguageServer sends the messages to a server and returns everything it
+// answered, in order.
+func runLanguageServer(t *testing.T, messages ...string) []lspMessage {
+	t.Helper()
+
+	var in, out bytes.Buffer
+	for _, message := range messages {
+		fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(message), message)
+	}
+	if err := lsp.NewServer(&in, &out).Serve(); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+
+	replies := make([]lspMessage, 0)
+	r := bufio.NewReader(&out)
+	for {
+		headers, err := textproto.NewReader(r).ReadMIMEHeader()
+		if err == io.EOF {
+			return replies
+		}
+		if err != nil {
+			t.Fatalf("reading reply headers: %v", err)
+		}
+		length, _ := strconv.Atoi(headers.Get("Content-Length"))
+		body := make([]byte, length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			t.Fatalf("reading reply: %v", err)
+		}
+
+		var reply lspMessage
+		if err := json.Unmarshal(body, &reply); err != nil {
+			t.Fatalf("decoding reply %s: %v", body, err)
+		}
+		replies = append(replies, reply)
+	}
+}
+
+func didOpen(text string) string {
+	encoded, _ := json.Marshal(text)
+	return `{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"` + lspURI +
+		`","languageId":"pogo","version":1,"text":` + string(encoded) + `}}}`
+}
+
+func positionRequest(id int, method string, line, character int) string {
+	return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"%s","params":{"textDocument":{"uri":"%s"},"position":{"line":%d,"character":%d}}}`,
+		id, method, lspURI, line, character)
+}
+
+func TestLanguageServer(t *testing.T) {
+	replies := runLanguageServer(t,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"initialized","params":{}}`,
+		didOpen(lspSource),
+		// move in the call of the main section
+		positionRequest(2, "textDocument/definition", 17, 5),
+		// pt.x inside move
+		positionRequest(3, "textDocument/hover", 10, 8),
+		// step in the main section
+		positionRequest(4, "textDocument/hover", 17, 18),
+		positionRequest(5, "textDocument/completion", 18, 4),
+		positionRequest(6, "textDocument/completion", 10, 7),
+		`{"jsonrpc":"2.0","id":7,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	)
+
+	if len(replies) != 8 {
+		t.Fatalf("expected 8 replies, got %d", len(replies))
+	}
+	if !strings.Contains(string(replies[0].Result), `"definitionProvider":true`) {
+		t.Errorf("initialize: unexpected capabilities %s", replies[0].Result)
+	}
+	if replies[1].Method != "textDocument/publishDiagnostics" || !strings.Contains(string(replies[1].Params), `"diagnostics":[]`) {
+		t.Errorf("expected no diagnostics, got %s", replies[1].Params)
+	}
+
+	var location lsp.Location
+	if err := json.Unmarshal(replies[2].Result, &location); err != nil {
+		t.Fatalf("definition: %v", err)
+	}
+	if location.URI != lspURI || location.Range.Start != (lsp.Position{Line: 9, Character: 5}) ||
+		location.Range.End != (lsp.Position{Line: 9, Character: 9}) {
+		t.Errorf("definition: unexpected location %+v", location)
+	}
+
+	for i, want := range map[int]string{3: "pt.x : float", 4: "step : float"} {
+		var hover lsp.Hover
+		if err := json.Unmarshal(replies[i].Result, &hover); err != nil {
+			t.Fatalf("hover: %v", err)
+		}
+		if !strings.Contains(hover.Contents.Value, want) {
+			t.Errorf("hover: expected %q, got %q", want, hover.Contents.Value)
+		}
+	}
+
+	var items []lsp.CompletionItem
+	if err := json.Unmarshal(replies[5].Result, &items); err != nil {
+		t.Fatalf("completion: %v", err)
+	}
+	labels := make(map[string]bool)
+	for _, item := range items {
+		labels[item.Label] = true
+	}
+	for _, want := range []string{"step", "origin", "count", "move", "Point", "sqrt", "while"} {
+		if !labels[want] {
+			t.Errorf("completion: missing %q", want)
+		}
+	}
+	if labels["pt"] || labels["dx"] {
+		t.Errorf("completion: parameters of move are not in scope in the main section")
+	}
+
+	if err := json.Unmarshal(replies[6].Result, &items); err != nil {
+		t.Fatalf("field completion: %v", err)
+	}
+	if len(items) != 2 || items[0].Label != "x" || items[1].Label != "y" {
+		t.Errorf("field completion: expected x and y, got %+v", items)
+	}
+}
+
+func TestLanguageServerDiagnostics(t *testing.T) {
+	src := strings.Replace(lspSource, "count = count + 1;", "count = missing + 1;", 1)
+	replies := runLanguageServer(t, didOpen(src), `{"jsonrpc":"2.0","id":1,"method":"unknown/method"}`)
+
+	if len(replies) != 2 {
+		t.Fatalf("expected 2 replies, got %d", len(replies))
+	}
+	var params struct {
+		Diagnostics []lsp.Diagnostic `json:"diagnostics"`
+	}
+	if err := json.Unmarshal(replies[0].Params, &params); err != nil {
+		t.Fatalf("diagnostics: %v", err)
+	}
+	if len(params.Diagnostics) != 1 {
+		t.Fatalf("expected one diagnostic, got %+v", params.Diagnostics)
+	}
+	diagnostic := params.Diagnostics[0]
+	if diagnostic.Range.Start.Line != 11 || diagnostic.Severity != lsp.SeverityError || !strings.Contains(diagnostic.Message, "missing") {
+		t.Errorf("unexpected diagnostic %+v", diagnostic)
+	}
+	if replies[1].Error == nil || replies[1].Error.Code != -32601 {
+		t.Errorf("expected a method not found error, got %+v", replies[1])
+	}
+}
+
+func TestLanguageServerPartialInput(t *testing.T) {
+	// Every prefix of the file is what an editor sends while it is typed
+	messages := make([]string, 0, len(lspSource)+1)
+	for i := 0; i <= len(lspSource); i++ {
+		messages = append(messages, didOpen(lspSource[:i]))
+	}
+	replies := runLanguageServer(t, messages...)
+
+	if len(replies) != len(messages) {
+		t.Fatalf("expected %d replies, got %d", len(messages), len(replies))
+	}
+	for i, reply := range replies {
+		if reply.Method != "textDocument/publishDiagnostics" {
+			t.Fatalf("prefix %d: unexpected reply %+v", i, reply)
+		}
+	}
+	if !strings.Contains(string(replies[len(replies)-1].Params), `"diagnostics":[]`) {
+		t.Errorf("expected no diagnostics for the whole file, got %s", replies[len(replies)-1].Params)
+	}
+}
+
+func TestLanguageServerUTF16Positions(t *testing.T) {
+	// Characters are UTF-16 code units, é is one and 😀 two, a tab is one
+	src := "program wide;\nbegin\n\tvar step : int = 2;\n\tprint(\"é😀\", step)\nend\n"
+	replies := runLanguageServer(t,
+		didOpen(src),
+		positionRequest(1, "textDocument/definition", 3, 14),
+		positionRequest(2, "textDocument/hover", 3, 17),
+	)
+
+	if len(replies) != 3 {
+		t.Fatalf("expected 3 replies, got %d", len(replies))
+	}
+	var location lsp.Location
+	if err := json.Unmarshal(replies[1].Result, &location); err != nil {
+		t.Fatalf("definition: %v", err)
+	}
+	if location.Range != (lsp.Range{Start: lsp.Position{Line: 2, Character: 5}, End: lsp.Position{Line: 2, Character: 9}}) {
+		t.Errorf("definition: unexpected range %+v", location.Range)
+	}
+
+	var hover lsp.Hover
+	if err := json.Unmarshal(replies[2].Result, &hover); err != nil {
+		t.Fatalf("hover: %v", err)
+	}
+	if hover.Range == nil || *hover.Range != (lsp.Range{Start: lsp.Position{Line: 3, Character: 14}, End: lsp.Position{Line: 3, Character: 18}}) {
+		t.Errorf("hover: unexpected range %+v", hover.Range)
+	}
+}
@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"pogo"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func newTestNatives(t *testing.T, log *[]string) *pogo.Natives {
+	t.Helper()
+
+	natives := pogo.NewNatives()
+	err := natives.RegisterNative("now", func(args []pogo.Value) (pogo.Value, error) {
+		return pogo.IntValue(1700000000), nil
+	}, pogo.Signature{Result: pogo.TypeInt})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = natives.RegisterNative("scale", func(args []pogo.Value) (pogo.Value, error) {
+		return pogo.FloatValue(args[0].Float * args[1].AsFloat()), nil
+	}, pogo.Signature{Parameters: []pogo.Type{pogo.TypeFloat, pogo.TypeInt}, Result: pogo.TypeFloat})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = natives.RegisterNative("log", func(args []pogo.Value) (pogo.Value, error) {
+		*log = append(*log, fmt.Sprint(args[0]))
+		return pogo.VoidValue(), nil
+	}, pogo.Signature{Parameters: []pogo.Type{pogo.TypeInt}, Result: pogo.TypeVoid})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return natives
+}
+
+func TestNativeFunctions(t *testing.T) {
+	const src = `
+program host;
+var stamp : int;
+
+begin
+    stamp = now() - 1700000000;
+    log(stamp + 5)
+    print(stamp, scale(2, 3), -scale(1.5, 2))
+end
+`
+	var log []string
+	natives := newTestNatives(t, &log)
+
+	program, err := pogo.Compile([]byte(src), pogo.CompileOptions{Natives: natives})
+	if err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := program.Run(context.Background(), pogo.RunOptions{Output: &out}); err != nil {
+		t.Fatalf("run error: %v", err)
+	}
+	if out.String() != "0 6.00 -3.00 \n" || len(log) != 1 || log[0] != "5" {
+		t.Fatalf("unexpected output %q, log %v", out.String(), log)
+	}
+
+	// A saved program needs the natives again when it runs
+	binary := filepath.Join(t.TempDir(), "host.pbin")
+	if err := program.Save(binary); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := pogo.Load(binary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = loaded.Run(context.Background(), pogo.RunOptions{Output: &out})
+	if err == nil || !strings.Contains(err.Error(), "native function 'now' is not registered") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := loaded.Run(context.Background(), pogo.RunOptions{Output: &out, Natives: natives}); err != nil {
+		t.Fatalf("run error: %v", err)
+	}
+}
+
+func TestNativeSignatureErrors(t *testing.T) {
+	var log []string
+	natives := newTestNatives(t, &log)
+
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"program h;\nvar x : int;\nbegin\n    x = now(1);\nend\n", "native function 'now' expects arguments"},
+		{"program h;\nbegin\n    log(2.5)\nend\n", "native function 'log' expects arguments"},
+		{"program h;\nvar x : int;\nbegin\n    x = log(1);\nend\n", "does not return a value"},
+	}
+
+	for _, tt := range tests {
+		_, err := pogo.Compile([]byte(tt.src), pogo.CompileOptions{Natives: natives})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("expected error containing %q, got %v", tt.want, err)
+		}
+	}
+
+	if err := natives.RegisterNative("now", nil, pogo.Signature{}); err == nil {
+		t.Errorf("expected an error registering a function without implementation")
+	}
+}
+
+func TestLinkedNatives(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.pogo":  {Data: []byte("program host;\nimport \"stamp.pogo\";\n\nbegin\n    stamp(now() - 1700000000)\nend\n")},
+		"stamp.pogo": {Data: []byte("module stamp;\n\nfunc stamp(n : int) {\n    log(n + 1)\n};\n")},
+	}
+
+	var log []string
+	natives := newTestNatives(t, &log)
+	compile := func(filename string, natives *pogo.Natives) *pogo.Object {
+		object, err := pogo.CompileObject(fsys[filename].Data, pogo.CompileOptions{Filename: filename, FS: fsys, Natives: natives})
+		if err != nil {
+			t.Fatalf("compiling %s: %v", filename, err)
+		}
+		return object
+	}
+
+	program, err := pogo.Link(compile("main.pogo", natives), compile("stamp.pogo", natives))
+	if err != nil {
+		t.Fatalf("link error: %v", err)
+	}
+	if err := program.Run(context.Background(), pogo.RunOptions{}); err != nil {
+		t.Fatalf("run error: %v", err)
+	}
+	if len(log) != 1 || log[0] != "1" {
+		t.Fatalf("unexpected log %v", log)
+	}
+
+	_, err = pogo.Link(compile("main.pogo", natives), compile("stamp.pogo", newTestNatives(t, &log)))
+	if err == nil || !strings.Contains(err.Error(), "objects compiled with different natives") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNativesRegisteredWhileCompiling(t *testing.T) {
+	var log []string
+	natives := newTestNatives(t, &log)
+
+	done := make(chan error)
+	go func() {
+		for i := 0; i < 50; i++ {
+			err := natives.RegisterNative(fmt.Sprintf("extra%d", i), func(args []pogo.Value) (pogo.Value, error) {
+				return pogo.VoidValue(), nil
+			}, pogo.Signature{Result: pogo.TypeVoid})
+			if err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	for i := 0; i < 50; i++ {
+		if _, err := pogo.Compile([]byte("program h;\nbegin\n    log(now())\nend\n"), pogo.CompileOptions{Natives: natives}); err != nil {
+			t.Fatalf("compile error: %v", err)
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := natives.Lookup("extra49"); !ok {
+		t.Fatalf("expected every native to be registered")
+	}
+}
@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"pogo"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// withMain returns the test modules together with a main.pogo program.
+func withMain(src string) fstest.MapFS {
+	fsys := fstest.MapFS{"main.pogo": {Data: []byte(src)}}
+	for name, file := range modules {
+		fsys[name] = file
+	}
+	return fsys
+}
+
+func compileObject(t *testing.T, fsys fstest.MapFS, filename string) *pogo.Object {
+	t.Helper()
+	src, err := fsys.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	object, err := pogo.CompileObject(src, pogo.CompileOptions{Filename: filename, FS: fsys})
+	if err != nil {
+		t.Fatalf("compiling %s: %v", filename, err)
+	}
+	return object
+}
+
+func TestLinkObjects(t *testing.T) {
+	fsys := withMain(`
+program shapes;
+import "lib/mathutils.pogo";
+import "lib/report.pogo";
+
+begin
+    square(3)
+    report()
+end
+`)
+
+	// Objects go through files to check they keep everything needed to link
+	dir := t.TempDir()
+	var objects []*pogo.Object
+	for _, name := range []string{"main.pogo", "lib/counter.pogo", "lib/mathutils.pogo", "lib/report.pogo"} {
+		filename := filepath.Join(dir, strings.ReplaceAll(name, "/", "_")+".pbin")
+		if err := compileObject(t, fsys, name).Save(filename); err != nil {
+			t.Fatal(err)
+		}
+		object, err := pogo.LoadObject(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, object)
+	}
+
+	program, err := pogo.Link(objects...)
+	if err != nil {
+		t.Fatalf("link failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := program.Run(context.Background(), pogo.RunOptions{Output: &out}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	want := "count 1 \n9 4.50 \ncount 11 \n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
+
+func TestLinkModuleGlobals(t *testing.T) {
+	fsys := withMain(moduleGlobalsProgram)
+
+	// The program comes first to check that modules are laid out before
+	// the units that use their globals
+	var objects []*pogo.Object
+	for _, name := range []string{"main.pogo", "lib/stats.pogo", "lib/counter.pogo"} {
+		objects = append(objects, compileObject(t, fsys, name))
+	}
+
+	program, err := pogo.Link(objects...)
+	if err != nil {
+		t.Fatalf("link failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := program.Run(context.Background(), pogo.RunOptions{Output: &out}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if out.String() != moduleGlobalsOutput {
+		t.Errorf("got %q, want %q", out.String(), moduleGlobalsOutput)
+	}
+
+	_, err = pogo.Link(objects[0], objects[1])
+	if err == nil || !strings.Contains(err.Error(), "unresolved external variable 'count'") {
+		t.Errorf("got error %v, want an unresolved external variable", err)
+	}
+}
+
+func TestModuleStructs(t *testing.T) {
+	const src = `
+program m;
+import "lib/geo.pogo";
+var a : Point;
+
+begin
+    a.x = 1;
+    a.y = 2;
+    shift(a, 0.5)
+    print(geo.origin.x, geo.origin.y)
+    geo.origin = a;
+    print(geo.origin.x)
+end
+`
+	const want = "1.50 2.00 \n1.00 \n"
+	fsys := withMain(src)
+
+	compiled, err := pogo.Compile([]byte(src), pogo.CompileOptions{Filename: "main.pogo", FS: fsys})
+	if err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+	linked, err := pogo.Link(compileObject(t, fsys, "main.pogo"), compileObject(t, fsys, "lib/geo.pogo"))
+	if err != nil {
+		t.Fatalf("link failed: %v", err)
+	}
+
+	for name, program := range map[string]*pogo.Program{"compiled": compiled, "linked": linked} {
+		var out bytes.Buffer
+		if err := program.Run(context.Background(), pogo.RunOptions{Output: &out}); err != nil {
+			t.Fatalf("%s: run failed: %v", name, err)
+		}
+		if out.String() != want {
+			t.Errorf("%s: got %q, want %q", name, out.String(), want)
+		}
+	}
+}
+
+func TestLinkErrors(t *testing.T) {
+	fsys := withMain("program p;\nimport \"lib/report.pogo\";\nbegin\n    report()\nend\n")
+
+	main := compileObject(t, fsys, "main.pogo")
+	report := compileObject(t, fsys, "lib/report.pogo")
+	counter := compileObject(t, fsys, "lib/counter.pogo")
+
+	tests := []struct {
+		name    string
+		objects []*pogo.Object
+		want    string
+	}{
+		{"unresolved external", []*pogo.Object{main, report}, "unresolved external function 'bump'"},
+		{"no main", []*pogo.Object{report, counter}, "no object has a main section"},
+		{"two mains", []*pogo.Object{main, report, counter, main}, "main section"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := pogo.Link(tt.objects...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadObjectAsProgram(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "counter.pbin")
+	if err := compileObject(t, modules, "lib/counter.pogo").Save(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := pogo.Load(filename)
+	if err == nil || !strings.Contains(err.Error(), "object file") {
+		t.Errorf("got error %v, want an object file error", err)
+	}
+}
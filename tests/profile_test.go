@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"pogo"
+	"pogo/src/virtualmachine"
+	"strings"
+	"testing"
+)
+
+func TestProfile(t *testing.T) {
+	const src = `program profiled;
+var r : int;
+func fib(n : int, ref out : int) {
+    var a, b : int;
+    if (n < 2) {
+        out = n;
+    } else {
+        fib(n - 1, a)
+        fib(n - 2, b)
+        out = a + b;
+    }
+};
+begin
+    fib(10, r)
+    print(r)
+end`
+
+	program, err := pogo.Compile([]byte(src), pogo.CompileOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+
+	var out bytes.Buffer
+	profile := virtualmachine.NewProfile()
+	profile.Filename = "profiled.pogo"
+	if err := program.Run(context.Background(), pogo.RunOptions{Output: &out, Profile: profile}); err != nil {
+		t.Fatalf("runtime error: %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "55" {
+		t.Fatalf("profiling changed the output: %q", out.String())
+	}
+
+	fib := profile.Functions["fib"]
+	if fib == nil || fib.Calls != 177 || profile.Opcodes["gosub"] != 177 || profile.Opcodes["endproc"] != 177 {
+		t.Fatalf("unexpected counts: fib %+v, opcodes %v", fib, profile.Opcodes)
+	}
+	if profile.Lines[6] != 89 {
+		t.Fatalf("expected line 6 to run for the 89 base cases, got %v", profile.Lines)
+	}
+
+	body := profile.Functions[virtualmachine.ProgramBody]
+	if body.Quads+fib.Quads != profile.Quads || body.Self+fib.Self != profile.Duration {
+		t.Fatalf("expected the functions to add up to the run: %+v %+v %d quads in %v", body, fib, profile.Quads, profile.Duration)
+	}
+	if fib.Total > body.Total || body.Total != profile.Duration {
+		t.Fatalf("expected recursive calls to be timed once: fib %v, program %v", fib.Total, body.Total)
+	}
+
+	var report bytes.Buffer
+	if err := profile.WriteReport(&report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{"function", "fib", "gosub", "line"} {
+		if !strings.Contains(report.String(), expected) {
+			t.Fatalf("expected %q in the report:\n%s", expected, report.String())
+		}
+	}
+
+	var pprof bytes.Buffer
+	if err := profile.WritePprof(&pprof); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gz, err := gzip.NewReader(&pprof)
+	if err != nil {
+		t.Fatalf("expected a gzipped profile: %v", err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("expected a gzipped profile: %v", err)
+	}
+	for _, name := range []string{"fib", "profiled.pogo", "nanoseconds"} {
+		if !bytes.Contains(data, []byte(name)) {
+			t.Fatalf("expected %q in the string table of the profile", name)
+		}
+	}
+}
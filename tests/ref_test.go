@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"pogo/src/virtualmachine"
+	"testing"
+)
+
+func TestRefParameters(t *testing.T) {
+	const src = `
+program refs;
+
+type Point struct {
+    x, y : float;
+}
+
+var a, b : int;
+var p : Point;
+
+func swap(ref x : int, ref y : int) {
+    var tmp : int;
+    tmp = x;
+    x = y;
+    y = tmp;
+};
+
+func twice(ref n : int) {
+    swap(n, b)
+    n = n * 2;
+};
+
+func sum(ref total : int, n : int) {
+    if (n > 0) {
+        total = total + n;
+        sum(total, n - 1)
+    }
+};
+
+func shift(ref pt : Point, ref dx : float) {
+    pt.x = pt.x + dx;
+    dx = 0;
+};
+
+func locals() {
+    var i, j : int;
+    i = 1;
+    j = 2;
+    swap(i, j)
+    sum(i, 3)
+    print("locals", i, j)
+};
+
+begin
+    a = 1;
+    b = 2;
+    swap(a, b)
+    print(a, b)
+    twice(a)
+    print(a, b)
+    locals()
+    p.x = 1;
+    p.y = 5;
+    shift(p, p.y)
+    print(p.x, p.y)
+end
+`
+	got := runPogo(t, src)
+	want := "2 1 \n2 2 \nlocals 8 1 \n6.00 0.00 \n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRefParameterErrors(t *testing.T) {
+	const header = "program p;\nconst k : int = 1;\nvar a : int;\nvar f : float;\nfunc inc(ref n : int) {\n    n = n + 1;\n};\n"
+
+	tests := []struct{ src, want string }{
+		{header + "begin\n    inc(a + 1)\nend\n", "line 9: argument for parameter 'n' must be a variable, it is passed by reference"},
+		{header + "begin\n    inc(k)\nend\n", "line 9: argument for parameter 'n' in function 'inc' must be a variable, it is passed by reference"},
+		{header + "begin\n    inc(f)\nend\n", "line 9: invalid argument type for reference parameter 'n' in function 'inc': expected int, got float"},
+	}
+	for _, tt := range tests {
+		if err := compileError(t, tt.src); err.Error() != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, err)
+		}
+	}
+}
+
+func TestStoreThroughReferenceChecksType(t *testing.T) {
+	// A float parameter referring to an int local of the caller
+	mm := virtualmachine.NewMemoryManager()
+	mm.PushNewFunctionSegment(false, 1, 0)
+	mm.PrepareFunctionSegment(0, 1)
+	if err := mm.StoreParamRef(virtualmachine.LOCAL_FLOAT_START, virtualmachine.LOCAL_INT_START); err != nil {
+		t.Fatal(err)
+	}
+	if err := mm.PushPreparedSegment(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := mm.Store(virtualmachine.LOCAL_FLOAT_START, 1.5)
+	if err == nil || err.Error() != "cannot store float value 1.5 at int address 4000" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package tests
+
+import "testing"
+
+func TestBlockScopedVariables(t *testing.T) {
+	const src = `
+program scopes;
+var x : int;
+
+func show(n : int) {
+    print("n", n)
+    if (n > 0) {
+        var n : float;
+        n = 2.5;
+        print("inner n", n)
+    }
+    var after : int;
+    after = n + 1;
+    print("after", after)
+};
+
+begin
+    x = 1;
+    if (x == 1) {
+        var x : int;
+        x = 7;
+        print("shadowed", x)
+    }
+    print("global", x)
+    if (x == 1) {
+        var t : float;
+        t = 0.5;
+        print("float t", t)
+    }
+    if (x == 1) {
+        var t : int;
+        t = 9;
+        print("int t", t)
+    }
+    show(3)
+end
+`
+	got := runPogo(t, src)
+	want := "shadowed 7 \nglobal 1 \nfloat t 0.50 \nint t 9 \nn 3 \ninner n 2.50 \nafter 4 \n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestBlockScopeErrors(t *testing.T) {
+	const outside = `program errs;
+begin
+    if (1 > 0) {
+        var y : int;
+        y = 1;
+    }
+    y = 2;
+end
+`
+	if err := compileError(t, outside); err.Error() != "line 7: undefined variable 'y'" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	const redeclared = "program errs;\nbegin\n    if (1 > 0) {\n        var y : int;\n        var y : float;\n    }\nend\n"
+	if err := compileError(t, redeclared); err.Error() != "line 5: symbol 'y' already declared in current scope" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMainLocals(t *testing.T) {
+	const src = `
+program mainlocals;
+
+type Point struct {
+    x, y : int;
+}
+
+var total : int = 0;
+
+func add(ref sum : int, n : int) {
+    sum = sum + n;
+    total = total + 1;
+};
+
+begin
+    var total : float = 0.5;
+    var i, sum : int;
+    var p : Point;
+    total = total * 3;
+    i = 1;
+    sum = 0;
+    while (i < 4) {
+        var step : int = i * 10;
+        add(sum, step)
+        i = i + 1;
+    }
+    p.x = sum;
+    p.y = i;
+    print(total, sum, p.x + p.y)
+end
+`
+	got := runPogo(t, src)
+	want := "1.50 60 64 \n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestMainLocalErrors(t *testing.T) {
+	const src = `program p;
+func f() {
+    print(secret)
+};
+begin
+    var secret : int = 1;
+    f()
+end
+`
+	if err := compileError(t, src); err.Error() != "line 3: variable 'secret' not declared in accessible scope" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
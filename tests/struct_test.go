@@ -0,0 +1,91 @@
+package tests
+
+import "testing"
+
+func TestStructs(t *testing.T) {
+	const src = `
+program shapes;
+
+type Point struct {
+    x, y : float;
+}
+
+type Rect struct {
+    origin : Point;
+    w, h : float;
+    id : int;
+};
+
+var p, q : Point;
+var r : Rect;
+var total : float;
+
+func area(rect : Rect, scale : int) {
+    var corner : Point;
+    corner = rect.origin;
+    corner.x = corner.x + rect.w;
+    total = rect.w * rect.h * scale;
+    print("area", rect.id, total, corner.x, corner.y)
+};
+
+func move(pt : Point, dx : float) {
+    pt.x = pt.x + dx;
+    print("moved", pt.x, pt.y)
+};
+
+begin
+    p.x = 1;
+    p.y = 2.5;
+    q = p;
+    q.x = q.x * 10;
+    r.origin = q;
+    r.w = 3;
+    r.h = 4;
+    r.id = 7;
+    area(r, 2)
+    move(p, 0.5)
+    move(r.origin, r.w)
+    print(p.x, q.x, r.origin.x, total)
+end
+`
+	got := runPogo(t, src)
+	// Structs are copied on assignment and passed by value
+	want := "area 7 24.00 13.00 2.50 \nmoved 1.50 2.50 \nmoved 13.00 2.50 \n1.00 10.00 10.00 24.00 \n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestMultipleParameters(t *testing.T) {
+	const src = `
+program params;
+
+func show(a : int, b : float, c : int) {
+    print(a, b, c)
+};
+
+begin
+    show(1, 2.5, 3)
+end
+`
+	got := runPogo(t, src)
+	want := "1 2.50 3 \n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestStructErrors(t *testing.T) {
+	const header = "program p;\ntype Point struct { x, y : float; }\ntype Pair struct { a, b : int; }\nvar p : Point;\nvar q : Pair;\n"
+
+	tests := []struct{ src, want string }{
+		{header + "begin\n    print(p)\nend\n", "line 7: 'p' is a struct, only its fields can be used in expressions"},
+		{header + "begin\n    p = q;\nend\n", "line 7: expected a value of type Point, 'q' is a Pair"},
+		{"program p;\ntype Line struct { a, b : Point; }\nbegin\nend\n", "line 2: undefined type 'Point'"},
+	}
+	for _, tt := range tests {
+		if err := compileError(t, tt.src); err.Error() != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"pogo"
+	"pogo/src/virtualmachine"
+	"strings"
+	"testing"
+)
+
+const traced = `program traced;
+var total : int;
+func add(n : int) {
+    total = total + n;
+};
+begin
+    total = 1;
+    add(2)
+    print(total)
+end`
+
+func traceRun(t *testing.T, src string, trace *virtualmachine.Trace) string {
+	t.Helper()
+	program, err := pogo.Compile([]byte(src), pogo.CompileOptions{})
+	if err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+
+	var out bytes.Buffer
+	trace.Output = &out
+	if err := program.Run(context.Background(), pogo.RunOptions{Output: &bytes.Buffer{}, Trace: trace}); err != nil {
+		t.Fatalf("runtime error: %v", err)
+	}
+	return out.String()
+}
+
+func TestTrace(t *testing.T) {
+	got := traceRun(t, traced, &virtualmachine.Trace{})
+	if again := traceRun(t, traced, &virtualmachine.Trace{}); got != again {
+		t.Fatalf("expected the same trace on every run:\n%s\n%s", got, again)
+	}
+
+	for _, expected := range []string{
+		"<program>: = 12000=1 => 0=1\n",
+		"<program>: param 12001=2, 0 => 4000=2\n",
+		"<program>: gosub add, ",
+		"add: + 0=1, 4000=2 => 8000=3\n",
+		"add: = 8000=3 => 0=3\n",
+		"add: endproc\n",
+		"<program>: print 0=3\n",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Fatalf("expected %q in the trace:\n%s", expected, got)
+		}
+	}
+}
+
+func TestTraceFilters(t *testing.T) {
+	got := traceRun(t, traced, &virtualmachine.Trace{Functions: []string{"add"}})
+	for _, line := range strings.Split(strings.TrimSpace(got), "\n") {
+		if !strings.Contains(line, " add: ") {
+			t.Fatalf("expected only the quads of add, got %q", line)
+		}
+	}
+
+	r, err := virtualmachine.ParseQuadRange("1-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got = traceRun(t, traced, &virtualmachine.Trace{Ranges: []virtualmachine.QuadRange{r}})
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "1 ") || !strings.HasPrefix(lines[1], "2 ") {
+		t.Fatalf("expected quads 1 and 2, got:\n%s", got)
+	}
+
+	if _, err := virtualmachine.ParseQuadRange("9-3"); err == nil {
+		t.Fatalf("expected an error for a backwards range")
+	}
+}
+
+func TestTraceReference(t *testing.T) {
+	const src = `program traced;
+var total : int;
+func set(ref out : int) {
+    out = 7;
+};
+begin
+    set(total)
+end`
+	got := traceRun(t, src, &virtualmachine.Trace{})
+	if !strings.Contains(got, "set: = 12000=7 => 4000=7\n") {
+		t.Fatalf("expected the store through the reference once:\n%s", got)
+	}
+}